@@ -0,0 +1,101 @@
+// Package kstatus contains helpers to report the status of the Qdrant custom resources in a kstatus compliant way,
+// so tools like Flux and Argo CD can determine whether a change has been reconciled.
+// See https://github.com/kubernetes-sigs/cli-utils/blob/master/pkg/kstatus/README.md
+package kstatus
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//goland:noinspection GoUnusedConst
+const (
+	// ConditionReady is True if the resource is fully reconciled
+	ConditionReady = "Ready"
+	// ConditionReconciling is True if the controller is working on reconciling the resource
+	ConditionReconciling = "Reconciling"
+	// ConditionStalled is True if the controller can't make progress reconciling the resource
+	ConditionStalled = "Stalled"
+)
+
+// Status is the kstatus computed for a resource
+type Status string
+
+//goland:noinspection GoUnusedConst
+const (
+	// Current means the resource is fully reconciled
+	Current Status = "Current"
+	// InProgress means the resource is not (yet) fully reconciled
+	InProgress Status = "InProgress"
+	// Failed means the reconciliation of the resource failed
+	Failed Status = "Failed"
+)
+
+// Result is the kstatus computed for a resource with a message explaining it
+type Result struct {
+	// Status is the computed status
+	Status Status
+	// Message explains the status
+	Message string
+}
+
+// FromConditions computes the kstatus based on the observed generation and the Ready, Reconciling and Stalled conditions.
+// An observed generation of 0 is treated as not reported (by an older controller), so it isn't compared with the generation.
+// The returned bool is false if the conditions are not conclusive (the Ready condition is missing),
+// in which case the caller should fall back to a resource specific computation (e.g. based on the phase).
+func FromConditions(generation, observedGeneration int64, conditions []metav1.Condition) (Result, bool) {
+	if observedGeneration > 0 && observedGeneration < generation {
+		return Result{Status: InProgress, Message: "the latest generation is not observed yet"}, true
+	}
+	if c := meta.FindStatusCondition(conditions, ConditionStalled); c != nil && c.Status == metav1.ConditionTrue {
+		return Result{Status: Failed, Message: c.Message}, true
+	}
+	if c := meta.FindStatusCondition(conditions, ConditionReconciling); c != nil && c.Status == metav1.ConditionTrue {
+		return Result{Status: InProgress, Message: c.Message}, true
+	}
+	c := meta.FindStatusCondition(conditions, ConditionReady)
+	if c == nil {
+		return Result{}, false
+	}
+	if c.Status == metav1.ConditionTrue {
+		return Result{Status: Current, Message: c.Message}, true
+	}
+	return Result{Status: InProgress, Message: c.Message}, true
+}
+
+// SetReady marks the resource as reconciled.
+// The Ready condition is set to True, the Reconciling and Stalled conditions are removed.
+// Returns true if the conditions are changed.
+func SetReady(conditions *[]metav1.Condition, observedGeneration int64, reason, message string) bool {
+	changed := meta.RemoveStatusCondition(conditions, ConditionReconciling)
+	changed = meta.RemoveStatusCondition(conditions, ConditionStalled) || changed
+	return setCondition(conditions, ConditionReady, metav1.ConditionTrue, observedGeneration, reason, message) || changed
+}
+
+// SetReconciling marks the resource as being reconciled.
+// The Reconciling condition is set to True, the Ready condition to False and the Stalled condition is removed.
+// Returns true if the conditions are changed.
+func SetReconciling(conditions *[]metav1.Condition, observedGeneration int64, reason, message string) bool {
+	changed := meta.RemoveStatusCondition(conditions, ConditionStalled)
+	changed = setCondition(conditions, ConditionReady, metav1.ConditionFalse, observedGeneration, reason, message) || changed
+	return setCondition(conditions, ConditionReconciling, metav1.ConditionTrue, observedGeneration, reason, message) || changed
+}
+
+// SetStalled marks the resource as stalled, meaning reconciling can't make progress without intervention.
+// The Stalled condition is set to True, the Ready condition to False and the Reconciling condition is removed.
+// Returns true if the conditions are changed.
+func SetStalled(conditions *[]metav1.Condition, observedGeneration int64, reason, message string) bool {
+	changed := meta.RemoveStatusCondition(conditions, ConditionReconciling)
+	changed = setCondition(conditions, ConditionReady, metav1.ConditionFalse, observedGeneration, reason, message) || changed
+	return setCondition(conditions, ConditionStalled, metav1.ConditionTrue, observedGeneration, reason, message) || changed
+}
+
+func setCondition(conditions *[]metav1.Condition, conditionType string, status metav1.ConditionStatus, observedGeneration int64, reason, message string) bool {
+	return meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: observedGeneration,
+		Reason:             reason,
+		Message:            message,
+	})
+}
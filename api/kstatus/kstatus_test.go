@@ -0,0 +1,69 @@
+package kstatus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFromConditions(t *testing.T) {
+	ready := metav1.Condition{Type: ConditionReady, Status: metav1.ConditionTrue, Message: "ready"}
+	notReady := metav1.Condition{Type: ConditionReady, Status: metav1.ConditionFalse, Message: "not ready"}
+	reconciling := metav1.Condition{Type: ConditionReconciling, Status: metav1.ConditionTrue, Message: "reconciling"}
+	stalled := metav1.Condition{Type: ConditionStalled, Status: metav1.ConditionTrue, Message: "stalled"}
+
+	testCases := []struct {
+		name               string
+		generation         int64
+		observedGeneration int64
+		conditions         []metav1.Condition
+		expected           Result
+		expectedConclusive bool
+	}{
+		{name: "No conditions", generation: 1, observedGeneration: 1},
+		{name: "Observed generation not reported", generation: 2, conditions: []metav1.Condition{ready},
+			expected: Result{Status: Current, Message: "ready"}, expectedConclusive: true},
+		{name: "Outdated observed generation", generation: 2, observedGeneration: 1, conditions: []metav1.Condition{ready},
+			expected: Result{Status: InProgress, Message: "the latest generation is not observed yet"}, expectedConclusive: true},
+		{name: "Ready", generation: 2, observedGeneration: 2, conditions: []metav1.Condition{ready},
+			expected: Result{Status: Current, Message: "ready"}, expectedConclusive: true},
+		{name: "Not ready", generation: 2, observedGeneration: 2, conditions: []metav1.Condition{notReady},
+			expected: Result{Status: InProgress, Message: "not ready"}, expectedConclusive: true},
+		{name: "Reconciling", generation: 2, observedGeneration: 2, conditions: []metav1.Condition{notReady, reconciling},
+			expected: Result{Status: InProgress, Message: "reconciling"}, expectedConclusive: true},
+		{name: "Stalled wins over reconciling", generation: 2, observedGeneration: 2, conditions: []metav1.Condition{notReady, reconciling, stalled},
+			expected: Result{Status: Failed, Message: "stalled"}, expectedConclusive: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			result, conclusive := FromConditions(tt.generation, tt.observedGeneration, tt.conditions)
+			assert.Equal(t, tt.expectedConclusive, conclusive)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestSetConditions(t *testing.T) {
+	var conditions []metav1.Condition
+
+	assert.True(t, SetReconciling(&conditions, 1, "Progressing", "creating nodes"))
+	assert.True(t, meta.IsStatusConditionTrue(conditions, ConditionReconciling))
+	assert.True(t, meta.IsStatusConditionFalse(conditions, ConditionReady))
+	assert.False(t, SetReconciling(&conditions, 1, "Progressing", "creating nodes"), "no change expected")
+
+	assert.True(t, SetStalled(&conditions, 1, "FailedToCreate", "quota exceeded"))
+	assert.True(t, meta.IsStatusConditionTrue(conditions, ConditionStalled))
+	assert.Nil(t, meta.FindStatusCondition(conditions, ConditionReconciling))
+	result, _ := FromConditions(1, 1, conditions)
+	assert.Equal(t, Failed, result.Status)
+
+	assert.True(t, SetReady(&conditions, 2, "Reconciled", ""))
+	assert.Len(t, conditions, 1)
+	assert.True(t, meta.IsStatusConditionTrue(conditions, ConditionReady))
+	assert.Equal(t, int64(2), conditions[0].ObservedGeneration)
+	result, _ = FromConditions(2, 2, conditions)
+	assert.Equal(t, Current, result.Status)
+}
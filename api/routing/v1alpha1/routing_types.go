@@ -2,6 +2,8 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/qdrant/kubernetes-api/api/kstatus"
 )
 
 //+kubebuilder:object:root=true
@@ -35,6 +37,18 @@ func (r *QdrantClusterRouting) GetSpec() QdrantClusterRoutingSpec {
 	return r.Spec
 }
 
+// GetKStatus computes the kstatus of the routing.
+// If the route-manager doesn't report the Ready condition (yet), the status is derived from Bootstrapped.
+func (r *QdrantClusterRouting) GetKStatus() kstatus.Result {
+	if result, ok := kstatus.FromConditions(r.Generation, r.Status.ObservedGeneration, r.Status.Conditions); ok {
+		return result
+	}
+	if r.Status.Bootstrapped != nil && *r.Status.Bootstrapped {
+		return kstatus.Result{Status: kstatus.Current}
+	}
+	return kstatus.Result{Status: kstatus.InProgress, Message: "routing is not bootstrapped yet"}
+}
+
 // QdrantClusterRoutingSpec describes the configuration for routing towards Qdrant clusters.
 type QdrantClusterRoutingSpec struct {
 	// ClusterId specifies the unique identifier of the cluster.
@@ -175,6 +189,12 @@ type QdrantClusterRoutingStatus struct {
 	Bootstrapped *bool `json:"bootstrapped,omitempty"`
 	// Individual bootstrap status info (e.g. when multiple routes are available for this Qdrant cluster)
 	BootstrapInfos *[]BootstrapStatusInfo `json:"bootstrapInfos,omitempty"`
+	// ObservedGeneration is the 'Generation' of the resource that was last processed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions specifies the conditions of the resource, including the kstatus compliant Ready, Reconciling and Stalled conditions
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // BootstrapStatusInfo is part of QdrantClusterRoutingStatus.
@@ -5,6 +5,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"k8s.io/utils/ptr"
+
+	"github.com/qdrant/kubernetes-api/api/kstatus"
 )
 
 // TestQdrantClusterRoutingGetSpecIsNilSafe covers the entry point of the getter
@@ -94,3 +96,14 @@ func TestQdrantClusterRoutingSpecGetters(t *testing.T) {
 	assert.True(t, spec.GetEnableAccessLog())
 	assert.True(t, spec.GetMultiAZ())
 }
+
+// TestQdrantClusterRoutingGetKStatus covers the fallback on Bootstrapped for
+// route-managers which don't report the Ready condition yet.
+func TestQdrantClusterRoutingGetKStatus(t *testing.T) {
+	assert.Equal(t, kstatus.InProgress, (&QdrantClusterRouting{}).GetKStatus().Status)
+	assert.Equal(t, kstatus.Current, (&QdrantClusterRouting{Status: QdrantClusterRoutingStatus{Bootstrapped: ptr.To(true)}}).GetKStatus().Status)
+
+	stalled := &QdrantClusterRouting{Status: QdrantClusterRoutingStatus{Bootstrapped: ptr.To(true)}}
+	kstatus.SetStalled(&stalled.Status.Conditions, 0, "InvalidFQDN", "fqdn is not valid")
+	assert.Equal(t, kstatus.Result{Status: kstatus.Failed, Message: "fqdn is not valid"}, stalled.GetKStatus())
+}
@@ -0,0 +1,327 @@
+package v1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Autoscaling specifies the autoscaling policy of a cluster.
+type Autoscaling struct {
+	// MinSize specifies the minimum number of Qdrant nodes in the cluster.
+	// It should be at least the (default) replication factor of the collections.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MinSize int `json:"minSize"`
+	// MaxSize specifies the maximum number of Qdrant nodes in the cluster.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MaxSize int `json:"maxSize"`
+	// Vertical specifies the bounds for the resources of each Qdrant node.
+	// If not set, the resources of the nodes are not scaled.
+	// +optional
+	Vertical *VerticalAutoscaling `json:"vertical,omitempty"`
+	// TargetMemoryUtilization specifies the target memory utilization in percent of the memory limit of the nodes.
+	// If not set, the memory utilization isn't taken into account.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	TargetMemoryUtilization *int `json:"targetMemoryUtilization,omitempty"`
+	// TargetDiskUtilization specifies the target disk utilization in percent of the database volume of the nodes.
+	// If not set, the disk utilization isn't taken into account.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	TargetDiskUtilization *int `json:"targetDiskUtilization,omitempty"`
+	// ScaleUpStabilizationWindow specifies how long the utilization needs to be above the target, before the cluster is scaled up.
+	// +kubebuilder:default="5m"
+	// +optional
+	ScaleUpStabilizationWindow *metav1.Duration `json:"scaleUpStabilizationWindow,omitempty"`
+	// ScaleDownStabilizationWindow specifies how long the utilization needs to be below the target, before the cluster is scaled down.
+	// +kubebuilder:default="1h"
+	// +optional
+	ScaleDownStabilizationWindow *metav1.Duration `json:"scaleDownStabilizationWindow,omitempty"`
+}
+
+// VerticalAutoscaling specifies the bounds for the resources of each Qdrant node.
+type VerticalAutoscaling struct {
+	// Min specifies the minimum resources of each Qdrant node.
+	Min AutoscalingResources `json:"min"`
+	// Max specifies the maximum resources of each Qdrant node.
+	Max AutoscalingResources `json:"max"`
+}
+
+// AutoscalingResources specifies the resources of a Qdrant node used by the autoscaler.
+type AutoscalingResources struct {
+	// CPU specifies the CPU limit of a Qdrant node.
+	CPU string `json:"cpu"`
+	// Memory specifies the memory limit of a Qdrant node.
+	Memory string `json:"memory"`
+}
+
+// GetScaleUpStabilizationWindow returns the scale up stabilization window, taking the default (5m) into consideration.
+func (a *Autoscaling) GetScaleUpStabilizationWindow() time.Duration {
+	if a == nil || a.ScaleUpStabilizationWindow == nil {
+		return 5 * time.Minute
+	}
+	return a.ScaleUpStabilizationWindow.Duration
+}
+
+// GetScaleDownStabilizationWindow returns the scale down stabilization window, taking the default (1h) into consideration.
+func (a *Autoscaling) GetScaleDownStabilizationWindow() time.Duration {
+	if a == nil || a.ScaleDownStabilizationWindow == nil {
+		return time.Hour
+	}
+	return a.ScaleDownStabilizationWindow.Duration
+}
+
+// ClampSize returns the given size, limited to the MinSize and MaxSize bounds.
+func (a *Autoscaling) ClampSize(size int) int {
+	if a == nil {
+		return size
+	}
+	return max(a.MinSize, min(a.MaxSize, size))
+}
+
+// validateAutoscaling validates the autoscaling policy against the rest of the spec
+func (s QdrantClusterSpec) validateAutoscaling() error {
+	a := s.Autoscaling
+	if a == nil {
+		return nil
+	}
+	if a.MinSize > a.MaxSize {
+		return fmt.Errorf(".spec.autoscaling: minSize (%d) can not be greater than maxSize (%d)", a.MinSize, a.MaxSize)
+	}
+	if s.Size < a.MinSize || s.Size > a.MaxSize {
+		return fmt.Errorf(".spec.autoscaling: size (%d) must be between minSize (%d) and maxSize (%d)", s.Size, a.MinSize, a.MaxSize)
+	}
+	if rf := s.Config.GetCollection().GetReplicationFactor(); int64(a.MinSize) < rf {
+		return fmt.Errorf(".spec.autoscaling: minSize (%d) can not be less than the replication_factor (%d)", a.MinSize, rf)
+	}
+	for _, d := range []*metav1.Duration{a.ScaleUpStabilizationWindow, a.ScaleDownStabilizationWindow} {
+		if d != nil && d.Duration < 0 {
+			return fmt.Errorf(".spec.autoscaling: stabilization windows must not be negative")
+		}
+	}
+	if a.Vertical != nil {
+		if err := a.Vertical.validate(s.Resources); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate checks the vertical autoscaling bounds and the current resources against them
+func (v VerticalAutoscaling) validate(current Resources) error {
+	minCPU, err := resource.ParseQuantity(v.Min.CPU)
+	if err != nil {
+		return fmt.Errorf(".spec.autoscaling.vertical.min.cpu error: %w", err)
+	}
+	minMemory, err := resource.ParseQuantity(v.Min.Memory)
+	if err != nil {
+		return fmt.Errorf(".spec.autoscaling.vertical.min.memory error: %w", err)
+	}
+	maxCPU, err := resource.ParseQuantity(v.Max.CPU)
+	if err != nil {
+		return fmt.Errorf(".spec.autoscaling.vertical.max.cpu error: %w", err)
+	}
+	maxMemory, err := resource.ParseQuantity(v.Max.Memory)
+	if err != nil {
+		return fmt.Errorf(".spec.autoscaling.vertical.max.memory error: %w", err)
+	}
+	if minCPU.Cmp(maxCPU) > 0 {
+		return fmt.Errorf(".spec.autoscaling.vertical: min.cpu can not be greater than max.cpu")
+	}
+	if minMemory.Cmp(maxMemory) > 0 {
+		return fmt.Errorf(".spec.autoscaling.vertical: min.memory can not be greater than max.memory")
+	}
+	// The current resources are already validated by Resources.Validate
+	if cpu, err := resource.ParseQuantity(current.CPU); err == nil && (cpu.Cmp(minCPU) < 0 || cpu.Cmp(maxCPU) > 0) {
+		return fmt.Errorf(".spec.autoscaling.vertical: resources.cpu (%s) must be between min.cpu and max.cpu", current.CPU)
+	}
+	if memory, err := resource.ParseQuantity(current.Memory); err == nil && (memory.Cmp(minMemory) < 0 || memory.Cmp(maxMemory) > 0) {
+		return fmt.Errorf(".spec.autoscaling.vertical: resources.memory (%s) must be between min.memory and max.memory", current.Memory)
+	}
+	return nil
+}
+
+// ScalingDecision specifies the decision made by the autoscaler
+type ScalingDecision string
+
+//goland:noinspection GoUnusedConst
+const (
+	ScalingDecisionNone          ScalingDecision = "None"
+	ScalingDecisionScaleOut      ScalingDecision = "ScaleOut"
+	ScalingDecisionScaleIn       ScalingDecision = "ScaleIn"
+	ScalingDecisionScaleUp       ScalingDecision = "ScaleUp"
+	ScalingDecisionScaleDown     ScalingDecision = "ScaleDown"
+	ScalingDecisionLimitReached  ScalingDecision = "LimitReached"
+	ScalingDecisionNotStabilized ScalingDecision = "NotStabilized"
+)
+
+// AutoscalingStatus specifies the last decision of the autoscaler
+type AutoscalingStatus struct {
+	// LastDecision specifies the last decision of the autoscaler.
+	// ScaleOut and ScaleIn change the number of nodes, ScaleUp and ScaleDown change the resources of each node.
+	// +kubebuilder:validation:Enum=None;ScaleOut;ScaleIn;ScaleUp;ScaleDown;LimitReached;NotStabilized
+	// +optional
+	LastDecision ScalingDecision `json:"lastDecision,omitempty"`
+	// Reason specifies the reason for the last decision, like the observed utilization
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// LastDecisionTime specifies the time of the last decision
+	// +optional
+	LastDecisionTime *metav1.Time `json:"lastDecisionTime,omitempty"`
+	// LastScaleTime specifies the time the cluster was scaled (in or out, up or down) the last time
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+	// DesiredSize specifies the number of nodes as decided by the autoscaler
+	// +optional
+	DesiredSize int `json:"desiredSize,omitempty"`
+	// DesiredResources specifies the resources of each node as decided by the autoscaler
+	// +optional
+	DesiredResources *AutoscalingResources `json:"desiredResources,omitempty"`
+}
+
+// ScaledWithin returns true if the cluster has been scaled within the given window before now.
+func (s *AutoscalingStatus) ScaledWithin(now time.Time, window time.Duration) bool {
+	if s == nil || s.LastScaleTime == nil {
+		return false
+	}
+	return now.Before(s.LastScaleTime.Add(window))
+}
+
+// StorageAutoscaling specifies the policy to automatically expand the database volume of each node.
+type StorageAutoscaling struct {
+	// UsageThreshold specifies the disk usage in percent of the volume size, from which the volume is expanded.
+	// +kubebuilder:default=80
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	UsageThreshold *int `json:"usageThreshold,omitempty"`
+	// Step specifies how much the volume is expanded each time.
+	// The value can be an absolute quantity (e.g. 10Gi) or a percentage of the current volume size (e.g. 20%).
+	// +kubebuilder:default="20%"
+	// +optional
+	Step string `json:"step,omitempty"`
+	// MaxSize specifies the maximum size of the volume.
+	MaxSize string `json:"maxSize"`
+}
+
+// GetUsageThreshold returns the usage threshold in percent, taking the default (80) into consideration.
+func (a *StorageAutoscaling) GetUsageThreshold() int {
+	if a == nil || a.UsageThreshold == nil {
+		return 80
+	}
+	return *a.UsageThreshold
+}
+
+// GetStep returns the step, taking the default (20%) into consideration.
+func (a *StorageAutoscaling) GetStep() string {
+	if a == nil || a.Step == "" {
+		return "20%"
+	}
+	return a.Step
+}
+
+// Validate if there are incorrect settings in the storage autoscaling policy.
+// The storage is the current storage size of each node (see Resources.Storage).
+func (a *StorageAutoscaling) Validate(storage string) error {
+	if a == nil {
+		return nil
+	}
+	if t := a.GetUsageThreshold(); t < 1 || t > 99 {
+		return fmt.Errorf(".spec.storageAutoscaling.usageThreshold: must be between 1 and 99")
+	}
+	if _, _, err := parseStorageStep(a.GetStep()); err != nil {
+		return fmt.Errorf(".spec.storageAutoscaling.step error: %w", err)
+	}
+	maxSize, err := resource.ParseQuantity(a.MaxSize)
+	if err != nil {
+		return fmt.Errorf(".spec.storageAutoscaling.maxSize error: %w", err)
+	}
+	// The storage is already validated by Resources.Validate
+	if current, err := resource.ParseQuantity(storage); err == nil && maxSize.Cmp(current) < 0 {
+		return fmt.Errorf(".spec.storageAutoscaling.maxSize: can not be less than resources.storage (%s)", storage)
+	}
+	return nil
+}
+
+// NextSize returns the size the volume should be expanded to, given the current size and the used space.
+// The returned bool is false if the volume doesn't need to be (or can't be) expanded,
+// because the usage is below the threshold, or the volume already reached the maximum size.
+// The result is rounded up to a whole GiB and never exceeds MaxSize.
+func (a *StorageAutoscaling) NextSize(current, used resource.Quantity) (resource.Quantity, bool) {
+	if a == nil || current.IsZero() {
+		return current, false
+	}
+	maxSize, err := resource.ParseQuantity(a.MaxSize)
+	if err != nil || current.Cmp(maxSize) >= 0 {
+		return current, false
+	}
+	if used.AsApproximateFloat64()*100 < current.AsApproximateFloat64()*float64(a.GetUsageThreshold()) {
+		return current, false
+	}
+	quantity, percent, err := parseStorageStep(a.GetStep())
+	if err != nil {
+		return current, false
+	}
+	step := quantity.Value()
+	if percent > 0 {
+		step = current.Value() * int64(percent) / 100
+	}
+	const gib = int64(1 << 30)
+	next := current.Value() + max(step, 1)
+	next = (next + gib - 1) / gib * gib
+	if next >= maxSize.Value() {
+		return maxSize, true
+	}
+	return *resource.NewQuantity(next, resource.BinarySI), true
+}
+
+// parseStorageStep parses a step which is either a quantity or a percentage.
+// Exactly one of the returned quantity and percentage is non-zero.
+func parseStorageStep(step string) (resource.Quantity, int, error) {
+	if p, ok := strings.CutSuffix(step, "%"); ok {
+		percent, err := strconv.Atoi(p)
+		if err != nil || percent < 1 {
+			return resource.Quantity{}, 0, fmt.Errorf("invalid percentage %q", step)
+		}
+		return resource.Quantity{}, percent, nil
+	}
+	quantity, err := resource.ParseQuantity(step)
+	if err != nil {
+		return resource.Quantity{}, 0, err
+	}
+	if quantity.Sign() <= 0 {
+		return resource.Quantity{}, 0, fmt.Errorf("must be positive")
+	}
+	return quantity, 0, nil
+}
+
+// ValidateStorageAutoscaling validates the storage autoscaling policy against the capabilities of the region
+// the cluster is running in. The policy is rejected if the selected storage class for the database volume
+// (see StorageClassNames.DB) doesn't allow volume expansion.
+func (s QdrantClusterSpec) ValidateStorageAutoscaling(region QdrantCloudRegionStatus) error {
+	if s.StorageAutoscaling == nil {
+		return nil
+	}
+	if c := region.Capabilities; c != nil && c.VolumeExpansion != nil && !*c.VolumeExpansion {
+		return fmt.Errorf(".spec.storageAutoscaling: the region doesn't support volume expansion")
+	}
+	sc := region.GetStorageClass(s.StorageClassNames.GetDB())
+	if sc == nil {
+		if name := s.StorageClassNames.GetDB(); name != nil && *name != "" {
+			return fmt.Errorf(".spec.storageAutoscaling: storage class %q not found in the region", *name)
+		}
+		return fmt.Errorf(".spec.storageAutoscaling: no default storage class found in the region")
+	}
+	if !sc.AllowVolumeExpansion {
+		return fmt.Errorf(".spec.storageAutoscaling: storage class %q doesn't allow volume expansion", sc.Name)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidateAutoscaling(t *testing.T) {
+	resources := Resources{CPU: "1", Memory: "4Gi", Storage: "10Gi"}
+	replicationFactor := func(rf int64) *QdrantConfiguration {
+		return &QdrantConfiguration{Collection: &QdrantConfigurationCollection{ReplicationFactor: ptr.To(rf)}}
+	}
+	testCases := []struct {
+		name          string
+		spec          QdrantClusterSpec
+		expectedError string
+	}{
+		{
+			name: "Valid horizontal autoscaling",
+			spec: QdrantClusterSpec{Size: 3, Resources: resources, Config: replicationFactor(2),
+				Autoscaling: &Autoscaling{MinSize: 2, MaxSize: 6, TargetMemoryUtilization: ptr.To(80)}},
+		},
+		{
+			name: "Valid vertical autoscaling",
+			spec: QdrantClusterSpec{Size: 3, Resources: resources,
+				Autoscaling: &Autoscaling{MinSize: 3, MaxSize: 3, Vertical: &VerticalAutoscaling{
+					Min: AutoscalingResources{CPU: "500m", Memory: "2Gi"},
+					Max: AutoscalingResources{CPU: "4", Memory: "16Gi"},
+				}}},
+		},
+		{
+			name:          "Min size greater than max size",
+			spec:          QdrantClusterSpec{Size: 3, Resources: resources, Autoscaling: &Autoscaling{MinSize: 4, MaxSize: 2}},
+			expectedError: ".spec.autoscaling: minSize (4) can not be greater than maxSize (2)",
+		},
+		{
+			name:          "Size out of bounds",
+			spec:          QdrantClusterSpec{Size: 1, Resources: resources, Autoscaling: &Autoscaling{MinSize: 2, MaxSize: 4}},
+			expectedError: ".spec.autoscaling: size (1) must be between minSize (2) and maxSize (4)",
+		},
+		{
+			name: "Min size less than replication factor",
+			spec: QdrantClusterSpec{Size: 3, Resources: resources, Config: replicationFactor(3),
+				Autoscaling: &Autoscaling{MinSize: 2, MaxSize: 4}},
+			expectedError: ".spec.autoscaling: minSize (2) can not be less than the replication_factor (3)",
+		},
+		{
+			name: "Negative stabilization window",
+			spec: QdrantClusterSpec{Size: 3, Resources: resources,
+				Autoscaling: &Autoscaling{MinSize: 1, MaxSize: 4, ScaleDownStabilizationWindow: &metav1.Duration{Duration: -time.Minute}}},
+			expectedError: ".spec.autoscaling: stabilization windows must not be negative",
+		},
+		{
+			name: "Invalid vertical bounds",
+			spec: QdrantClusterSpec{Size: 3, Resources: resources,
+				Autoscaling: &Autoscaling{MinSize: 1, MaxSize: 4, Vertical: &VerticalAutoscaling{
+					Min: AutoscalingResources{CPU: "2", Memory: "2Gi"},
+					Max: AutoscalingResources{CPU: "1", Memory: "16Gi"},
+				}}},
+			expectedError: ".spec.autoscaling.vertical: min.cpu can not be greater than max.cpu",
+		},
+		{
+			name: "Resources out of vertical bounds",
+			spec: QdrantClusterSpec{Size: 3, Resources: resources,
+				Autoscaling: &Autoscaling{MinSize: 1, MaxSize: 4, Vertical: &VerticalAutoscaling{
+					Min: AutoscalingResources{CPU: "500m", Memory: "8Gi"},
+					Max: AutoscalingResources{CPU: "4", Memory: "16Gi"},
+				}}},
+			expectedError: ".spec.autoscaling.vertical: resources.memory (4Gi) must be between min.memory and max.memory",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate()
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestAutoscalingHelpers(t *testing.T) {
+	var a *Autoscaling
+	assert.Equal(t, 5, a.ClampSize(5))
+	assert.Equal(t, 5*time.Minute, a.GetScaleUpStabilizationWindow())
+	assert.Equal(t, time.Hour, a.GetScaleDownStabilizationWindow())
+
+	a = &Autoscaling{MinSize: 2, MaxSize: 4}
+	assert.Equal(t, 2, a.ClampSize(1))
+	assert.Equal(t, 3, a.ClampSize(3))
+	assert.Equal(t, 4, a.ClampSize(10))
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	status := &AutoscalingStatus{LastScaleTime: ptr.To(metav1.NewTime(now.Add(-10 * time.Minute)))}
+	assert.True(t, status.ScaledWithin(now, time.Hour))
+	assert.False(t, status.ScaledWithin(now, 5*time.Minute))
+	assert.False(t, (*AutoscalingStatus)(nil).ScaledWithin(now, time.Hour))
+}
+
+func TestStorageAutoscalingNextSize(t *testing.T) {
+	testCases := []struct {
+		name         string
+		policy       *StorageAutoscaling
+		current      string
+		used         string
+		expectedSize string
+		expectedOk   bool
+	}{
+		{name: "No policy", policy: nil, current: "10Gi", used: "10Gi", expectedSize: "10Gi"},
+		{name: "Below threshold", policy: &StorageAutoscaling{MaxSize: "100Gi"}, current: "10Gi", used: "7Gi", expectedSize: "10Gi"},
+		{name: "Default step of 20%", policy: &StorageAutoscaling{MaxSize: "100Gi"}, current: "10Gi", used: "8Gi", expectedSize: "12Gi", expectedOk: true},
+		{name: "Percentage rounded up to a whole GiB", policy: &StorageAutoscaling{MaxSize: "100Gi", Step: "10%"}, current: "15Gi", used: "15Gi", expectedSize: "17Gi", expectedOk: true},
+		{name: "Absolute step", policy: &StorageAutoscaling{MaxSize: "100Gi", Step: "5Gi", UsageThreshold: ptr.To(90)}, current: "10Gi", used: "9500Mi", expectedSize: "15Gi", expectedOk: true},
+		{name: "Capped at max size", policy: &StorageAutoscaling{MaxSize: "11Gi"}, current: "10Gi", used: "9Gi", expectedSize: "11Gi", expectedOk: true},
+		{name: "Max size reached", policy: &StorageAutoscaling{MaxSize: "10Gi"}, current: "10Gi", used: "10Gi", expectedSize: "10Gi"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			size, ok := tt.policy.NextSize(resource.MustParse(tt.current), resource.MustParse(tt.used))
+			assert.Equal(t, tt.expectedOk, ok)
+			assert.Equal(t, 0, size.Cmp(resource.MustParse(tt.expectedSize)), "expected %s, got %s", tt.expectedSize, size.String())
+		})
+	}
+}
+
+func TestStorageAutoscalingValidate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		policy        *StorageAutoscaling
+		expectedError string
+	}{
+		{name: "No policy", policy: nil},
+		{name: "Valid policy", policy: &StorageAutoscaling{MaxSize: "100Gi", Step: "10Gi"}},
+		{name: "Invalid threshold", policy: &StorageAutoscaling{MaxSize: "100Gi", UsageThreshold: ptr.To(100)}, expectedError: ".spec.storageAutoscaling.usageThreshold: must be between 1 and 99"},
+		{name: "Invalid percentage", policy: &StorageAutoscaling{MaxSize: "100Gi", Step: "x%"}, expectedError: `.spec.storageAutoscaling.step error: invalid percentage "x%"`},
+		{name: "Negative step", policy: &StorageAutoscaling{MaxSize: "100Gi", Step: "-1Gi"}, expectedError: ".spec.storageAutoscaling.step error: must be positive"},
+		{name: "Max size below storage", policy: &StorageAutoscaling{MaxSize: "5Gi"}, expectedError: ".spec.storageAutoscaling.maxSize: can not be less than resources.storage (10Gi)"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate("10Gi")
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateStorageAutoscaling(t *testing.T) {
+	region := QdrantCloudRegionStatus{
+		StorageClasses: []StorageClass{
+			{Name: "standard", Default: true, AllowVolumeExpansion: false},
+			{Name: "expandable", AllowVolumeExpansion: true},
+		},
+	}
+	policy := &StorageAutoscaling{MaxSize: "100Gi"}
+	testCases := []struct {
+		name          string
+		spec          QdrantClusterSpec
+		region        QdrantCloudRegionStatus
+		expectedError string
+	}{
+		{name: "No policy", spec: QdrantClusterSpec{}, region: region},
+		{
+			name:   "Expandable storage class",
+			spec:   QdrantClusterSpec{StorageAutoscaling: policy, StorageClassNames: &StorageClassNames{DB: ptr.To("expandable")}},
+			region: region,
+		},
+		{
+			name:          "Default storage class can not expand",
+			spec:          QdrantClusterSpec{StorageAutoscaling: policy},
+			region:        region,
+			expectedError: `.spec.storageAutoscaling: storage class "standard" doesn't allow volume expansion`,
+		},
+		{
+			name:          "Unknown storage class",
+			spec:          QdrantClusterSpec{StorageAutoscaling: policy, StorageClassNames: &StorageClassNames{DB: ptr.To("unknown")}},
+			region:        region,
+			expectedError: `.spec.storageAutoscaling: storage class "unknown" not found in the region`,
+		},
+		{
+			name: "Region without volume expansion",
+			spec: QdrantClusterSpec{StorageAutoscaling: policy, StorageClassNames: &StorageClassNames{DB: ptr.To("expandable")}},
+			region: QdrantCloudRegionStatus{
+				Capabilities:   &RegionCapabilities{VolumeExpansion: ptr.To(false)},
+				StorageClasses: region.StorageClasses,
+			},
+			expectedError: ".spec.storageAutoscaling: the region doesn't support volume expansion",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.ValidateStorageAutoscaling(tt.region)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package v1
+
+import (
+	"cmp"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The limits of the CollectionsStatus, to keep the QdrantCluster object well below the etcd object size limit.
+// See CollectionsStatus.Truncate.
+//
+//goland:noinspection GoUnusedConst
+const (
+	// MaxCollectionStatuses is the maximum number of collections reported in detail
+	MaxCollectionStatuses = 100
+	// MaxShardReplicaStatuses is the maximum number of shard replicas reported over all collections
+	MaxShardReplicaStatuses = 1000
+	// MaxShardTransferStatuses is the maximum number of shard transfers reported
+	MaxShardTransferStatuses = 50
+)
+
+// ShardReplicaState specifies the state of a shard replica, as reported by Qdrant
+type ShardReplicaState string
+
+//goland:noinspection GoUnusedConst
+const (
+	ShardReplicaActive          ShardReplicaState = "Active"
+	ShardReplicaDead            ShardReplicaState = "Dead"
+	ShardReplicaPartial         ShardReplicaState = "Partial"
+	ShardReplicaInitializing    ShardReplicaState = "Initializing"
+	ShardReplicaListener        ShardReplicaState = "Listener"
+	ShardReplicaPartialSnapshot ShardReplicaState = "PartialSnapshot"
+	ShardReplicaRecovery        ShardReplicaState = "Recovery"
+	ShardReplicaResharding      ShardReplicaState = "Resharding"
+)
+
+// CollectionsStatus specifies the status of the collections in the cluster
+type CollectionsStatus struct {
+	// Count specifies the total number of collections in the cluster
+	// +optional
+	Count int `json:"count,omitempty"`
+	// UnreplicatedCount specifies the number of collections which have at least one shard without an active replica on another peer.
+	// Restarting a node of such a collection makes (part of) the data unavailable.
+	// +optional
+	UnreplicatedCount int `json:"unreplicatedCount,omitempty"`
+	// Items specifies the status of the individual collections.
+	// Collections with problems (unreplicated or not active shard replicas) are listed first.
+	// If there are more than 100 collections or 1000 shard replicas, the list is truncated (see Truncated).
+	// +kubebuilder:validation:MaxItems=100
+	// +optional
+	Items []CollectionStatus `json:"items,omitempty"`
+	// ShardTransfers specifies the shard transfers in progress.
+	// If there are more than 50 transfers, the list is truncated (see Truncated).
+	// +kubebuilder:validation:MaxItems=50
+	// +optional
+	ShardTransfers []ShardTransferStatus `json:"shardTransfers,omitempty"`
+	// Truncated is set if Items, their Shards or ShardTransfers are truncated.
+	// +optional
+	Truncated bool `json:"truncated,omitempty"`
+	// LastUpdateTime specifies the time this status was retrieved from Qdrant
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// CollectionStatus specifies the status of a collection
+type CollectionStatus struct {
+	// Name specifies the name of the collection
+	Name string `json:"name"`
+	// Points specifies the (approximate) number of points in the collection
+	// +optional
+	Points int64 `json:"points,omitempty"`
+	// Vectors specifies the (approximate) number of vectors in the collection
+	// +optional
+	Vectors int64 `json:"vectors,omitempty"`
+	// ReplicationFactor specifies the configured replication factor of the collection
+	// +optional
+	ReplicationFactor int `json:"replicationFactor,omitempty"`
+	// Shards specifies the replicas of the shards of the collection
+	// +optional
+	Shards []ShardStatus `json:"shards,omitempty"`
+}
+
+// ShardStatus specifies the status of a shard
+type ShardStatus struct {
+	// ShardId specifies the id of the shard
+	ShardId int `json:"shardId"`
+	// Replicas specifies the replicas of the shard
+	// +optional
+	Replicas []ShardReplicaStatus `json:"replicas,omitempty"`
+}
+
+// ShardReplicaStatus specifies the status of a shard replica
+type ShardReplicaStatus struct {
+	// PeerId specifies the consensus peer id the replica is located on
+	// (a string, because the id is an unsigned 64-bit number)
+	PeerId string `json:"peerId"`
+	// State specifies the state of the replica
+	State ShardReplicaState `json:"state"`
+}
+
+// ShardTransferStatus specifies the status of a shard transfer
+type ShardTransferStatus struct {
+	// Collection specifies the name of the collection
+	Collection string `json:"collection"`
+	// ShardId specifies the id of the transferred shard
+	ShardId int `json:"shardId"`
+	// From specifies the consensus peer id the shard is transferred from
+	From string `json:"from"`
+	// To specifies the consensus peer id the shard is transferred to
+	To string `json:"to"`
+	// Method specifies the method used for the transfer, like stream_records or snapshot
+	// +optional
+	Method string `json:"method,omitempty"`
+	// Sync specifies whether the transfer is a replication (true), or a move (false)
+	// +optional
+	Sync bool `json:"sync,omitempty"`
+}
+
+// IsUnreplicated returns true if at least one shard of the collection has less than 2 active replicas.
+func (c CollectionStatus) IsUnreplicated() bool {
+	for _, shard := range c.Shards {
+		if shard.ActiveReplicas() < 2 {
+			return true
+		}
+	}
+	return false
+}
+
+// IsHealthy returns true if all shard replicas of the collection are active.
+func (c CollectionStatus) IsHealthy() bool {
+	for _, shard := range c.Shards {
+		if shard.ActiveReplicas() != len(shard.Replicas) {
+			return false
+		}
+	}
+	return true
+}
+
+// ActiveReplicas returns the number of active replicas of the shard.
+func (s ShardStatus) ActiveReplicas() int {
+	result := 0
+	for _, r := range s.Replicas {
+		if r.State == ShardReplicaActive {
+			result++
+		}
+	}
+	return result
+}
+
+// HasUnreplicatedCollections returns true if at least one collection is unreplicated.
+// This is based on UnreplicatedCount, so it's correct even if Items is truncated.
+func (s *CollectionsStatus) HasUnreplicatedCollections() bool {
+	return s != nil && s.UnreplicatedCount > 0
+}
+
+// Truncate sorts the collections (the ones with problems first) and truncates the status to the limits
+// (MaxCollectionStatuses, MaxShardReplicaStatuses and MaxShardTransferStatuses).
+// Count and UnreplicatedCount should be set before, because they are not recomputed after truncation.
+func (s *CollectionsStatus) Truncate() {
+	if s == nil {
+		return
+	}
+	slices.SortStableFunc(s.Items, func(a, b CollectionStatus) int {
+		if rank := cmp.Compare(collectionRank(a), collectionRank(b)); rank != 0 {
+			return rank
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	if len(s.Items) > MaxCollectionStatuses {
+		s.Items = s.Items[:MaxCollectionStatuses]
+		s.Truncated = true
+	}
+	replicas := 0
+items:
+	for i := range s.Items {
+		for j := range s.Items[i].Shards {
+			remaining := MaxShardReplicaStatuses - replicas
+			shard := &s.Items[i].Shards[j]
+			if len(shard.Replicas) > remaining {
+				// Drop the remaining shards (and collections) completely, rather than reporting partial shards,
+				// including the collection itself if none of its shards are left
+				s.Items[i].Shards = s.Items[i].Shards[:j]
+				if j == 0 {
+					s.Items = s.Items[:i]
+				} else {
+					s.Items = s.Items[:i+1]
+				}
+				s.Truncated = true
+				break items
+			}
+			replicas += len(shard.Replicas)
+		}
+	}
+	if len(s.ShardTransfers) > MaxShardTransferStatuses {
+		s.ShardTransfers = s.ShardTransfers[:MaxShardTransferStatuses]
+		s.Truncated = true
+	}
+}
+
+// collectionRank ranks collections for reporting, lower is more important
+func collectionRank(c CollectionStatus) int {
+	switch {
+	case !c.IsHealthy():
+		return 0
+	case c.IsUnreplicated():
+		return 1
+	default:
+		return 2
+	}
+}
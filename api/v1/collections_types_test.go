@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func replicatedShards(shards, replicas int) []ShardStatus {
+	var result []ShardStatus
+	for s := 0; s < shards; s++ {
+		shard := ShardStatus{ShardId: s}
+		for r := 0; r < replicas; r++ {
+			shard.Replicas = append(shard.Replicas, ShardReplicaStatus{PeerId: fmt.Sprintf("%d", 1000+r), State: ShardReplicaActive})
+		}
+		result = append(result, shard)
+	}
+	return result
+}
+
+func TestCollectionStatusHelpers(t *testing.T) {
+	replicated := CollectionStatus{Name: "replicated", Shards: replicatedShards(2, 2)}
+	assert.False(t, replicated.IsUnreplicated())
+	assert.True(t, replicated.IsHealthy())
+
+	unreplicated := CollectionStatus{Name: "unreplicated", Shards: replicatedShards(2, 1)}
+	assert.True(t, unreplicated.IsUnreplicated())
+	assert.True(t, unreplicated.IsHealthy())
+
+	partial := CollectionStatus{Name: "partial", Shards: replicatedShards(1, 2)}
+	partial.Shards[0].Replicas[1].State = ShardReplicaPartial
+	assert.True(t, partial.IsUnreplicated(), "a partial replica doesn't count as a replica")
+	assert.False(t, partial.IsHealthy())
+
+	var status *CollectionsStatus
+	assert.False(t, status.HasUnreplicatedCollections())
+	assert.True(t, (&CollectionsStatus{UnreplicatedCount: 1}).HasUnreplicatedCollections())
+}
+
+func TestCollectionsStatusTruncateOrdersProblemsFirst(t *testing.T) {
+	dead := CollectionStatus{Name: "z-dead", Shards: replicatedShards(1, 2)}
+	dead.Shards[0].Replicas[0].State = ShardReplicaDead
+	status := &CollectionsStatus{
+		Items: []CollectionStatus{
+			{Name: "b-healthy", Shards: replicatedShards(1, 2)},
+			{Name: "a-healthy", Shards: replicatedShards(1, 2)},
+			{Name: "c-unreplicated", Shards: replicatedShards(1, 1)},
+			dead,
+		},
+	}
+	status.Truncate()
+
+	var names []string
+	for _, c := range status.Items {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"z-dead", "c-unreplicated", "a-healthy", "b-healthy"}, names)
+	assert.False(t, status.Truncated)
+}
+
+func TestCollectionsStatusTruncate(t *testing.T) {
+	status := &CollectionsStatus{Count: 500}
+	for i := 0; i < 500; i++ {
+		status.Items = append(status.Items, CollectionStatus{Name: fmt.Sprintf("collection-%03d", i), Shards: replicatedShards(6, 3)})
+	}
+	for i := 0; i < 80; i++ {
+		status.ShardTransfers = append(status.ShardTransfers, ShardTransferStatus{Collection: "collection-000", ShardId: i, From: "1", To: "2"})
+	}
+	status.Truncate()
+
+	assert.True(t, status.Truncated)
+	assert.Equal(t, 500, status.Count, "count is not changed by truncating")
+	assert.Len(t, status.ShardTransfers, MaxShardTransferStatuses)
+	assert.LessOrEqual(t, len(status.Items), MaxCollectionStatuses)
+	replicas := 0
+	for _, c := range status.Items {
+		for _, s := range c.Shards {
+			replicas += len(s.Replicas)
+		}
+	}
+	assert.LessOrEqual(t, replicas, MaxShardReplicaStatuses)
+
+	data, err := json.Marshal(status)
+	require.NoError(t, err)
+	assert.Less(t, len(data), 256*1024, "the truncated status should stay well below the etcd object size limit")
+}
+
+func TestCollectionsStatusTruncateDropsEmptyCollections(t *testing.T) {
+	status := &CollectionsStatus{
+		Items: []CollectionStatus{
+			{Name: "a", Shards: replicatedShards(10, MaxShardReplicaStatuses/10)},
+			{Name: "b", Shards: replicatedShards(1, 2)},
+		},
+	}
+	status.Truncate()
+	assert.True(t, status.Truncated)
+	require.Len(t, status.Items, 1)
+	assert.Equal(t, "a", status.Items[0].Name)
+	assert.Len(t, status.Items[0].Shards, 10)
+
+	status = &CollectionsStatus{
+		Items: []CollectionStatus{
+			{Name: "a", Shards: replicatedShards(5, MaxShardReplicaStatuses/10)},
+			{Name: "b", Shards: replicatedShards(6, MaxShardReplicaStatuses/10)},
+		},
+	}
+	status.Truncate()
+	assert.True(t, status.Truncated)
+	require.Len(t, status.Items, 2)
+	assert.Len(t, status.Items[1].Shards, 5)
+}
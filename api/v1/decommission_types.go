@@ -0,0 +1,125 @@
+package v1
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DecommissionPhase specifies the phase of a node being decommissioned
+type DecommissionPhase string
+
+//goland:noinspection GoUnusedConst
+const (
+	// DecommissionDraining means the shards of the node are being moved to other nodes
+	DecommissionDraining DecommissionPhase = "Draining"
+	// DecommissionRemoving means the node is drained and is being removed (including its consensus peer and PVCs)
+	DecommissionRemoving DecommissionPhase = "Removing"
+	// DecommissionCompleted means the node is removed
+	DecommissionCompleted DecommissionPhase = "Completed"
+	// DecommissionFailed means the node can't be decommissioned, see Message
+	DecommissionFailed DecommissionPhase = "Failed"
+)
+
+// NodeDecommissionStatus specifies the progress of a node being decommissioned
+type NodeDecommissionStatus struct {
+	// NodeIndex specifies the index of the node
+	NodeIndex int `json:"nodeIndex"`
+	// Phase specifies the phase of the decommissioning
+	Phase DecommissionPhase `json:"phase"`
+	// RemainingShards specifies the number of shard replicas which still need to be moved away from the node
+	// +optional
+	RemainingShards int `json:"remainingShards,omitempty"`
+	// Message specifies details about the phase
+	// +optional
+	Message string `json:"message,omitempty"`
+	// StartTime specifies when the decommissioning started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime specifies when the node was removed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// validateDecommission validates the nodes to decommission against the rest of the spec
+func (s QdrantClusterSpec) validateDecommission() error {
+	if len(s.DecommissionNodeIndexes) == 0 {
+		return nil
+	}
+	seen := make(map[int]bool, len(s.DecommissionNodeIndexes))
+	for _, idx := range s.DecommissionNodeIndexes {
+		if idx < 0 {
+			return fmt.Errorf(".spec.decommissionNodeIndexes: invalid node index %d", idx)
+		}
+		if seen[idx] {
+			return fmt.Errorf(".spec.decommissionNodeIndexes: duplicate node index %d", idx)
+		}
+		seen[idx] = true
+	}
+	// Size is the number of nodes after the decommissioning, the decommissioned nodes are replaced otherwise
+	if rf := s.Config.GetCollection().GetReplicationFactor(); int64(s.Size) < rf {
+		return fmt.Errorf(".spec.decommissionNodeIndexes: decommissioning leaves %d nodes, which is less than the replication_factor (%d)", s.Size, rf)
+	}
+	return nil
+}
+
+// IsDecommissioning returns true if the node with the given index should be decommissioned.
+func (s QdrantClusterSpec) IsDecommissioning(idx int) bool {
+	return slices.Contains(s.DecommissionNodeIndexes, idx)
+}
+
+// GetDecommissionStatus returns the decommission status of the node with the given index, nil if not found.
+func (s *QdrantClusterStatus) GetDecommissionStatus(idx int) *NodeDecommissionStatus {
+	if s == nil {
+		return nil
+	}
+	for i := range s.Decommissions {
+		if s.Decommissions[i].NodeIndex == idx {
+			return &s.Decommissions[i]
+		}
+	}
+	return nil
+}
+
+// SetDecommissionStatus sets the progress of decommissioning the node with the given index.
+// The StartTime is set on the first call, the CompletionTime when the decommissioning is completed.
+func (s *QdrantClusterStatus) SetDecommissionStatus(idx int, phase DecommissionPhase, remainingShards int, message string, now time.Time) {
+	status := s.GetDecommissionStatus(idx)
+	if status == nil {
+		s.Decommissions = append(s.Decommissions, NodeDecommissionStatus{NodeIndex: idx, StartTime: &metav1.Time{Time: now}})
+		status = &s.Decommissions[len(s.Decommissions)-1]
+	}
+	status.Phase = phase
+	status.RemainingShards = remainingShards
+	status.Message = message
+	if phase == DecommissionCompleted && status.CompletionTime == nil {
+		status.CompletionTime = &metav1.Time{Time: now}
+	}
+}
+
+// GetPendingDecommissions returns the indexes of the nodes which still need to be decommissioned.
+// Completed and failed decommissions are not pending. A failed decommission is retried
+// by removing the index from DecommissionNodeIndexes (see PruneDecommissionStatuses) and adding it again.
+func (qc *QdrantCluster) GetPendingDecommissions() []int {
+	var result []int
+	for _, idx := range qc.Spec.DecommissionNodeIndexes {
+		if s := qc.Status.GetDecommissionStatus(idx); s == nil || (s.Phase != DecommissionCompleted && s.Phase != DecommissionFailed) {
+			result = append(result, idx)
+		}
+	}
+	return result
+}
+
+// PruneDecommissionStatuses drops the statuses of the decommissions which are not completed,
+// and whose node index is not in the given DecommissionNodeIndexes anymore (e.g. to retry a failed decommission).
+// The statuses of completed decommissions are kept, since their nodes are removed.
+func (s *QdrantClusterStatus) PruneDecommissionStatuses(indexes []int) {
+	if s == nil {
+		return
+	}
+	s.Decommissions = slices.DeleteFunc(s.Decommissions, func(status NodeDecommissionStatus) bool {
+		return status.Phase != DecommissionCompleted && !slices.Contains(indexes, status.NodeIndex)
+	})
+}
@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidateDecommission(t *testing.T) {
+	resources := Resources{CPU: "1", Memory: "4Gi", Storage: "10Gi"}
+	config := &QdrantConfiguration{Collection: &QdrantConfigurationCollection{ReplicationFactor: ptr.To(int64(2))}}
+	testCases := []struct {
+		name          string
+		spec          QdrantClusterSpec
+		expectedError string
+	}{
+		{name: "Valid", spec: QdrantClusterSpec{Size: 2, Resources: resources, Config: config, DecommissionNodeIndexes: []int{2}}},
+		{name: "Replaced", spec: QdrantClusterSpec{Size: 3, Resources: resources, Config: config, DecommissionNodeIndexes: []int{0, 1}}},
+		{
+			name:          "Duplicate index",
+			spec:          QdrantClusterSpec{Size: 5, Resources: resources, DecommissionNodeIndexes: []int{1, 1}},
+			expectedError: ".spec.decommissionNodeIndexes: duplicate node index 1",
+		},
+		{
+			name:          "Negative index",
+			spec:          QdrantClusterSpec{Size: 5, Resources: resources, DecommissionNodeIndexes: []int{-1}},
+			expectedError: ".spec.decommissionNodeIndexes: invalid node index -1",
+		},
+		{
+			name:          "Too few remaining nodes",
+			spec:          QdrantClusterSpec{Size: 1, Resources: resources, Config: config, DecommissionNodeIndexes: []int{1, 2}},
+			expectedError: ".spec.decommissionNodeIndexes: decommissioning leaves 1 nodes, which is less than the replication_factor (2)",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate()
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestDecommissionStatus(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	qc := &QdrantCluster{
+		Spec:   QdrantClusterSpec{Size: 3, DecommissionNodeIndexes: []int{1, 3}},
+		Status: QdrantClusterStatus{AvailableNodeIndexes: []int{0, 1, 2, 3}},
+	}
+	assert.True(t, qc.Spec.IsDecommissioning(1))
+	assert.False(t, qc.Spec.IsDecommissioning(0))
+	assert.Equal(t, []int{1, 3}, qc.GetPendingDecommissions())
+	require.NoError(t, qc.ValidateOperations())
+
+	qc.Status.SetDecommissionStatus(1, DecommissionDraining, 12, "moving shards", now)
+	qc.Status.SetDecommissionStatus(1, DecommissionRemoving, 0, "", now.Add(time.Minute))
+	qc.Status.SetDecommissionStatus(1, DecommissionCompleted, 0, "", now.Add(2*time.Minute))
+	status := qc.Status.GetDecommissionStatus(1)
+	require.NotNil(t, status)
+	assert.Equal(t, now, status.StartTime.Time)
+	assert.Equal(t, now.Add(2*time.Minute), status.CompletionTime.Time)
+	assert.Equal(t, []int{3}, qc.GetPendingDecommissions())
+
+	// A failed decommission is not retried, until its index is removed and added again
+	qc.Status.SetDecommissionStatus(3, DecommissionFailed, 4, "not enough replicas", now)
+	assert.Empty(t, qc.GetPendingDecommissions())
+	qc.Status.PruneDecommissionStatuses(qc.Spec.DecommissionNodeIndexes)
+	assert.Empty(t, qc.GetPendingDecommissions())
+	qc.Status.PruneDecommissionStatuses([]int{1})
+	assert.Nil(t, qc.Status.GetDecommissionStatus(3))
+	assert.NotNil(t, qc.Status.GetDecommissionStatus(1), "completed decommissions are kept")
+	assert.Equal(t, []int{3}, qc.GetPendingDecommissions())
+
+	// A removed node which was decommissioned is still valid
+	qc.Status.AvailableNodeIndexes = []int{0, 2, 3}
+	require.NoError(t, qc.ValidateOperations())
+	qc.Spec.DecommissionNodeIndexes = []int{7}
+	assert.EqualError(t, qc.ValidateOperations(), ".spec.decommissionNodeIndexes: node index 7 doesn't exist")
+}
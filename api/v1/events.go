@@ -0,0 +1,152 @@
+package v1
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// MaxStatusSize is the size (in bytes) the serialized QdrantClusterStatus is trimmed to (see TrimToSize).
+// It is kept well below the etcd object size limit (1.5MiB), to leave room for the spec and metadata.
+const MaxStatusSize = 1024 * 1024
+
+// MergeEvents merges the events with the same reason and message into a single event,
+// summing up the counts and keeping the first and last timestamps.
+// The result is sorted by the last timestamp, oldest first.
+func MergeEvents(events []KubernetesEventInfo) []KubernetesEventInfo {
+	if len(events) == 0 {
+		return events
+	}
+	type key struct{ reason, message string }
+	indexes := make(map[key]int, len(events))
+	result := make([]KubernetesEventInfo, 0, len(events))
+	for _, event := range events {
+		if event.Count == 0 {
+			event.Count = 1
+		}
+		k := key{event.Reason, event.Message}
+		i, found := indexes[k]
+		if !found {
+			indexes[k] = len(result)
+			result = append(result, event)
+			continue
+		}
+		merged := &result[i]
+		merged.Count += event.Count
+		if !event.FirstTimestamp.IsZero() && (merged.FirstTimestamp.IsZero() || event.FirstTimestamp.Before(&merged.FirstTimestamp)) {
+			merged.FirstTimestamp = event.FirstTimestamp
+		}
+		if merged.LastTimestamp.Before(&event.LastTimestamp) {
+			merged.LastTimestamp = event.LastTimestamp
+		}
+	}
+	slices.SortStableFunc(result, func(a, b KubernetesEventInfo) int {
+		return a.LastTimestamp.Compare(b.LastTimestamp.Time)
+	})
+	return result
+}
+
+// MergeEvents merges the events of the volume snapshots (see MergeEvents).
+func (s *QdrantClusterSnapshotStatus) MergeEvents() {
+	for i := range s.VolumeSnapshots {
+		s.VolumeSnapshots[i].Events = MergeEvents(s.VolumeSnapshots[i].Events)
+	}
+}
+
+// EstimateSize returns the size of the serialized status in bytes.
+func (s *QdrantClusterStatus) EstimateSize() int {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return 0
+	}
+	return len(b)
+}
+
+// TrimToSize reduces the serialized size of the status to maxSize bytes (see MaxStatusSize),
+// by dropping the least relevant details first:
+//   - merging duplicate events
+//   - dropping events of the nodes, oldest first
+//   - dropping the phase history, oldest first
+//   - dropping the details of the collections
+//   - dropping the pod conditions and container statuses of the nodes
+//
+// Returns true if the status fits in maxSize, which is always the case for a nil status.
+func (s *QdrantClusterStatus) TrimToSize(maxSize int) bool {
+	if s == nil {
+		return true
+	}
+	// Work on copies of the nodes, since map values are not addressable
+	nodes := make([]*NodeStatus, 0, len(s.Nodes))
+	names := make([]string, 0, len(s.Nodes))
+	for name, node := range s.Nodes {
+		node.PodEvents = MergeEvents(node.PodEvents)
+		node.DatabasePVCStatus.Events = MergeEvents(node.DatabasePVCStatus.Events)
+		node.SnapshotsPVCStatus.Events = MergeEvents(node.SnapshotsPVCStatus.Events)
+		nodes = append(nodes, &node)
+		names = append(names, name)
+	}
+	defer func() {
+		for i, name := range names {
+			s.Nodes[name] = *nodes[i]
+		}
+	}()
+	size := s.estimateSizeWithNodes(names, nodes)
+	if size <= maxSize {
+		return true
+	}
+	// Drop the events, oldest first
+	events := make([]*[]KubernetesEventInfo, 0, 3*len(nodes))
+	for _, node := range nodes {
+		events = append(events, &node.PodEvents, &node.DatabasePVCStatus.Events, &node.SnapshotsPVCStatus.Events)
+	}
+	for size > maxSize {
+		oldest := -1
+		for i, list := range events {
+			// The lists are sorted by MergeEvents, so the first event is the oldest
+			if len(*list) > 0 && (oldest < 0 || (*list)[0].LastTimestamp.Before(&(*events[oldest])[0].LastTimestamp)) {
+				oldest = i
+			}
+		}
+		if oldest < 0 {
+			break
+		}
+		size -= jsonSize((*events[oldest])[0]) + 1
+		*events[oldest] = (*events[oldest])[1:]
+	}
+	size = s.estimateSizeWithNodes(names, nodes)
+	// Drop the phase history, oldest first
+	for size > maxSize && len(s.PhaseHistory) > 0 {
+		size -= jsonSize(s.PhaseHistory[0]) + 1
+		s.PhaseHistory = s.PhaseHistory[1:]
+	}
+	if size > maxSize && s.Collections != nil && (len(s.Collections.Items) > 0 || len(s.Collections.ShardTransfers) > 0) {
+		s.Collections.Items = nil
+		s.Collections.ShardTransfers = nil
+		s.Collections.Truncated = true
+		size = s.estimateSizeWithNodes(names, nodes)
+	}
+	for i := 0; i < len(nodes) && size > maxSize; i++ {
+		size -= jsonSize(nodes[i].PodConditions) + jsonSize(nodes[i].ContainerStatuses)
+		nodes[i].PodConditions = nil
+		nodes[i].ContainerStatuses = nil
+	}
+	return s.estimateSizeWithNodes(names, nodes) <= maxSize
+}
+
+// estimateSizeWithNodes returns the size of the serialized status, with the given (modified) nodes.
+func (s *QdrantClusterStatus) estimateSizeWithNodes(names []string, nodes []*NodeStatus) int {
+	status := *s
+	status.Nodes = make(map[string]NodeStatus, len(nodes))
+	for i, name := range names {
+		status.Nodes[name] = *nodes[i]
+	}
+	return status.EstimateSize()
+}
+
+// jsonSize returns the size of the serialized value in bytes.
+func jsonSize(v any) int {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return len(b)
+}
@@ -0,0 +1,100 @@
+package v1
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMergeEvents(t *testing.T) {
+	at := func(minute int) metav1.Time {
+		return metav1.NewTime(time.Date(2024, 1, 1, 0, minute, 0, 0, time.UTC))
+	}
+	events := []KubernetesEventInfo{
+		{Reason: "BackOff", Message: "Back-off restarting failed container", Count: 3, FirstTimestamp: at(5), LastTimestamp: at(10)},
+		{Reason: "Pulled", Message: "Container image pulled", Count: 1, FirstTimestamp: at(1), LastTimestamp: at(1)},
+		{Reason: "BackOff", Message: "Back-off restarting failed container", Count: 2, FirstTimestamp: at(2), LastTimestamp: at(8)},
+		{Reason: "BackOff", Message: "Back-off pulling image", FirstTimestamp: at(3), LastTimestamp: at(3)},
+	}
+
+	merged := MergeEvents(events)
+	assert.Equal(t, []KubernetesEventInfo{
+		{Reason: "Pulled", Message: "Container image pulled", Count: 1, FirstTimestamp: at(1), LastTimestamp: at(1)},
+		{Reason: "BackOff", Message: "Back-off pulling image", Count: 1, FirstTimestamp: at(3), LastTimestamp: at(3)},
+		{Reason: "BackOff", Message: "Back-off restarting failed container", Count: 5, FirstTimestamp: at(2), LastTimestamp: at(10)},
+	}, merged)
+	assert.Empty(t, MergeEvents(nil))
+}
+
+// worstCaseStatus returns a status of a cluster with the maximum number of nodes, all of them reporting a lot of events.
+func worstCaseStatus(eventsPerNode int) QdrantClusterStatus {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	status := QdrantClusterStatus{Phase: ClusterNotReady, Nodes: map[string]NodeStatus{}}
+	for i := 0; i < 100; i++ {
+		node := NodeStatus{
+			Name:          fmt.Sprintf("qdrant-node-%d", i),
+			PodConditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse, Message: strings.Repeat("x", 200)}},
+		}
+		for j := 0; j < eventsPerNode; j++ {
+			ts := metav1.NewTime(start.Add(time.Duration(j)*time.Minute + time.Duration(i)*time.Second))
+			event := KubernetesEventInfo{
+				Reason:         "FailedScheduling",
+				Message:        fmt.Sprintf("0/%d nodes are available: %s", j, strings.Repeat("insufficient memory, ", 20)),
+				Count:          1,
+				FirstTimestamp: ts,
+				LastTimestamp:  ts,
+			}
+			node.PodEvents = append(node.PodEvents, event)
+			node.DatabasePVCStatus.Events = append(node.DatabasePVCStatus.Events, event)
+		}
+		status.Nodes[node.Name] = node
+	}
+	return status
+}
+
+func TestTrimToSize(t *testing.T) {
+	status := worstCaseStatus(60)
+	require.Greater(t, status.EstimateSize(), MaxStatusSize)
+
+	assert.True(t, status.TrimToSize(MaxStatusSize))
+	assert.LessOrEqual(t, status.EstimateSize(), MaxStatusSize)
+	assert.Len(t, status.Nodes, 100)
+
+	// The oldest events are dropped first, so all the remaining events are newer than the dropped ones
+	var remaining int
+	oldestRemaining := time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, node := range status.Nodes {
+		assert.NotEmpty(t, node.PodConditions, "details are only dropped if dropping events is not enough")
+		for _, event := range append(node.PodEvents, node.DatabasePVCStatus.Events...) {
+			remaining++
+			if event.LastTimestamp.Time.Before(oldestRemaining) {
+				oldestRemaining = event.LastTimestamp.Time
+			}
+		}
+	}
+	assert.Less(t, remaining, 2*100*60)
+	assert.Greater(t, remaining, 0)
+	assert.True(t, oldestRemaining.After(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	// Nothing to trim
+	small := worstCaseStatus(1)
+	before := small.EstimateSize()
+	assert.True(t, small.TrimToSize(MaxStatusSize))
+	assert.Equal(t, before, small.EstimateSize())
+
+	// Details are dropped if events are not enough
+	tiny := worstCaseStatus(1)
+	assert.True(t, tiny.TrimToSize(10*1024))
+	for _, node := range tiny.Nodes {
+		assert.Empty(t, node.PodEvents)
+	}
+
+	var none *QdrantClusterStatus
+	assert.True(t, none.TrimToSize(MaxStatusSize))
+}
@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/qdrant/kubernetes-api/api/kstatus"
+)
+
+func TestQdrantClusterGetKStatus(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cluster  QdrantCluster
+		expected kstatus.Status
+	}{
+		{name: "No status", expected: kstatus.InProgress},
+		{name: "Healthy", cluster: QdrantCluster{Status: QdrantClusterStatus{Phase: ClusterHealthy}}, expected: kstatus.Current},
+		{name: "Suspended", cluster: QdrantCluster{Status: QdrantClusterStatus{Phase: ClusterSuspended}}, expected: kstatus.Current},
+		{name: "Scaling", cluster: QdrantCluster{Status: QdrantClusterStatus{Phase: ClusterScaling}}, expected: kstatus.InProgress},
+		{name: "Failed", cluster: QdrantCluster{Status: QdrantClusterStatus{Phase: ClusterFailedToUpdate}}, expected: kstatus.Failed},
+		{
+			name: "Healthy, but outdated generation",
+			cluster: QdrantCluster{
+				ObjectMeta: metav1.ObjectMeta{Generation: 3},
+				Status:     QdrantClusterStatus{Phase: ClusterHealthy, ObservedGeneration: 2},
+			},
+			expected: kstatus.InProgress,
+		},
+		{
+			name: "Ready condition wins over the phase",
+			cluster: QdrantCluster{
+				ObjectMeta: metav1.ObjectMeta{Generation: 3},
+				Status: QdrantClusterStatus{
+					Phase:              ClusterNotReady,
+					ObservedGeneration: 3,
+					Conditions:         []metav1.Condition{{Type: string(ClusterConditionReady), Status: metav1.ConditionTrue}},
+				},
+			},
+			expected: kstatus.Current,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.cluster.GetKStatus().Status)
+		})
+	}
+}
+
+func TestGetKStatusFromPhase(t *testing.T) {
+	assert.Equal(t, kstatus.Current, (&QdrantClusterSnapshot{Status: QdrantClusterSnapshotStatus{Phase: SnapshotSucceeded}}).GetKStatus().Status)
+	assert.Equal(t, kstatus.Current, (&QdrantClusterSnapshot{Status: QdrantClusterSnapshotStatus{Phase: SnapshotSkipped}}).GetKStatus().Status)
+	assert.Equal(t, kstatus.Failed, (&QdrantClusterSnapshot{Status: QdrantClusterSnapshotStatus{Phase: SnapshotFailed}}).GetKStatus().Status)
+	assert.Equal(t, kstatus.InProgress, (&QdrantClusterSnapshot{Status: QdrantClusterSnapshotStatus{Phase: SnapshotRunning}}).GetKStatus().Status)
+
+	assert.Equal(t, kstatus.Current, (&QdrantClusterRestore{Status: QdrantClusterRestoreStatus{Phase: RestoreSucceeded}}).GetKStatus().Status)
+	assert.Equal(t, kstatus.InProgress, (&QdrantClusterRestore{Status: QdrantClusterRestoreStatus{Phase: RestorePending}}).GetKStatus().Status)
+	failedRestore := (&QdrantClusterRestore{Status: QdrantClusterRestoreStatus{Phase: RestoreFailed, Message: ptr.To("snapshot not found")}}).GetKStatus()
+	assert.Equal(t, kstatus.Result{Status: kstatus.Failed, Message: "snapshot not found"}, failedRestore)
+
+	assert.Equal(t, kstatus.Current, (&QdrantClusterScheduledSnapshot{Status: QdrantClusterScheduledSnapshotStatus{Phase: ScheduleActive}}).GetKStatus().Status)
+	assert.Equal(t, kstatus.Current, (&QdrantClusterScheduledSnapshot{Status: QdrantClusterScheduledSnapshotStatus{Phase: ScheduleDisabled}}).GetKStatus().Status)
+	assert.Equal(t, kstatus.Failed, (&QdrantClusterScheduledSnapshot{Status: QdrantClusterScheduledSnapshotStatus{Phase: ScheduleDisabled, Message: ptr.To("invalid schedule")}}).GetKStatus().Status)
+
+	assert.Equal(t, kstatus.Current, (&QdrantCloudRegion{Status: QdrantCloudRegionStatus{Phase: RegionPhaseReady}}).GetKStatus().Status)
+	assert.Equal(t, kstatus.InProgress, (&QdrantCloudRegion{Status: QdrantCloudRegionStatus{Phase: RegionPhaseNotReady}}).GetKStatus().Status)
+	assert.Equal(t, kstatus.Failed, (&QdrantCloudRegion{Status: QdrantCloudRegionStatus{Phase: FailedToSync}}).GetKStatus().Status)
+}
@@ -0,0 +1,199 @@
+package v1
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Weekday specifies a day of the week.
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type Weekday string
+
+//goland:noinspection GoUnusedConst
+const (
+	Monday    Weekday = "Monday"
+	Tuesday   Weekday = "Tuesday"
+	Wednesday Weekday = "Wednesday"
+	Thursday  Weekday = "Thursday"
+	Friday    Weekday = "Friday"
+	Saturday  Weekday = "Saturday"
+	Sunday    Weekday = "Sunday"
+)
+
+// maintenanceWindowTimeLayout is the layout of the StartTime and EndTime of a MaintenanceWindow
+const maintenanceWindowTimeLayout = "15:04"
+
+// MaintenanceWindow specifies a recurring time window in which disruptive operations are allowed to start.
+type MaintenanceWindow struct {
+	// Days specifies the days of the week on which the maintenance window starts.
+	// If not set, the maintenance window starts every day.
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+	// StartTime specifies the time of the day the maintenance window starts, in HH:MM (24h) format.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	StartTime string `json:"startTime"`
+	// EndTime specifies the time of the day the maintenance window ends, in HH:MM (24h) format.
+	// If EndTime is before StartTime, the maintenance window ends on the next day.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	EndTime string `json:"endTime"`
+	// TimeZone specifies the IANA time zone name (e.g. "Europe/Berlin") in which StartTime and EndTime are expressed.
+	// +kubebuilder:default=UTC
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// Validate if there are incorrect settings in the maintenance window
+func (w MaintenanceWindow) Validate(base string) error {
+	start, err := time.Parse(maintenanceWindowTimeLayout, w.StartTime)
+	if err != nil {
+		return fmt.Errorf("%s.startTime: invalid time %q, expected HH:MM", base, w.StartTime)
+	}
+	end, err := time.Parse(maintenanceWindowTimeLayout, w.EndTime)
+	if err != nil {
+		return fmt.Errorf("%s.endTime: invalid time %q, expected HH:MM", base, w.EndTime)
+	}
+	if start.Equal(end) {
+		return fmt.Errorf("%s: startTime and endTime must differ", base)
+	}
+	if _, err := w.location(); err != nil {
+		return fmt.Errorf("%s.timeZone error: %w", base, err)
+	}
+	for _, d := range w.Days {
+		if _, err := d.toTimeWeekday(); err != nil {
+			return fmt.Errorf("%s.days error: %w", base, err)
+		}
+	}
+	return nil
+}
+
+// Next returns the start and end of the occurrence of the maintenance window which contains now,
+// or if now isn't in the maintenance window, the start and end of the next occurrence.
+// The returned bool is false if the maintenance window is invalid.
+func (w MaintenanceWindow) Next(now time.Time) (time.Time, time.Time, bool) {
+	loc, err := w.location()
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	startOfDay, err1 := time.Parse(maintenanceWindowTimeLayout, w.StartTime)
+	endOfDay, err2 := time.Parse(maintenanceWindowTimeLayout, w.EndTime)
+	if err1 != nil || err2 != nil || startOfDay.Equal(endOfDay) {
+		return time.Time{}, time.Time{}, false
+	}
+	local := now.In(loc)
+	// Start one day back, because an occurrence which started yesterday can still be in progress.
+	// Iterating over 8 days covers a full week after today, so each allowed weekday is checked.
+	for offset := -1; offset <= 7; offset++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, loc)
+		if !w.startsOn(day.Weekday()) {
+			continue
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), startOfDay.Hour(), startOfDay.Minute(), 0, 0, loc)
+		end := time.Date(day.Year(), day.Month(), day.Day(), endOfDay.Hour(), endOfDay.Minute(), 0, 0, loc)
+		if endOfDay.Before(startOfDay) {
+			end = time.Date(day.Year(), day.Month(), day.Day()+1, endOfDay.Hour(), endOfDay.Minute(), 0, 0, loc)
+		}
+		if end.After(now) {
+			return start, end, true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// Contains returns true if the given time is in an occurrence of the maintenance window.
+func (w MaintenanceWindow) Contains(now time.Time) bool {
+	start, _, ok := w.Next(now)
+	return ok && !start.After(now)
+}
+
+// startsOn returns true if the maintenance window starts on the given weekday.
+func (w MaintenanceWindow) startsOn(weekday time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if wd, err := d.toTimeWeekday(); err == nil && wd == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// location returns the time zone of the maintenance window, taking the default (UTC) into consideration.
+func (w MaintenanceWindow) location() (*time.Location, error) {
+	if w.TimeZone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(w.TimeZone)
+}
+
+// toTimeWeekday converts the Weekday to a time.Weekday
+func (d Weekday) toTimeWeekday() (time.Weekday, error) {
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		if string(d) == wd.String() {
+			return wd, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("invalid weekday %q", d)
+}
+
+// InMaintenanceWindow returns true if disruptive operations are allowed to start at the given time.
+// This is the case if no maintenance windows are configured, EmergencyMaintenance is enabled,
+// or the given time is in one of the maintenance windows.
+func (s QdrantClusterSpec) InMaintenanceWindow(now time.Time) bool {
+	if s.EmergencyMaintenance || len(s.MaintenanceWindows) == 0 {
+		return true
+	}
+	for _, w := range s.MaintenanceWindows {
+		if w.Contains(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextWindow returns the start and end of the maintenance window which contains now,
+// or if now isn't in a maintenance window, the start and end of the first upcoming maintenance window.
+// The returned bool is false if no (valid) maintenance windows are configured.
+func (s QdrantClusterSpec) NextWindow(now time.Time) (time.Time, time.Time, bool) {
+	var nextStart, nextEnd time.Time
+	found := false
+	for _, w := range s.MaintenanceWindows {
+		start, end, ok := w.Next(now)
+		if !ok {
+			continue
+		}
+		if !found || start.Before(nextStart) {
+			nextStart, nextEnd, found = start, end, true
+		}
+	}
+	return nextStart, nextEnd, found
+}
+
+// DisruptiveOperation specifies a type of operation which disrupts the availability of (part of) the cluster.
+// +kubebuilder:validation:Enum=Upgrade;Restart;OnDemandReplicationRestart;Rebalance
+type DisruptiveOperation string
+
+//goland:noinspection GoUnusedConst
+const (
+	DisruptiveOperationUpgrade                    DisruptiveOperation = "Upgrade"
+	DisruptiveOperationRestart                    DisruptiveOperation = "Restart"
+	DisruptiveOperationOnDemandReplicationRestart DisruptiveOperation = "OnDemandReplicationRestart"
+	DisruptiveOperationRebalance                  DisruptiveOperation = "Rebalance"
+)
+
+// DeferredOperation specifies a disruptive operation which is deferred until the next maintenance window.
+type DeferredOperation struct {
+	// Operation specifies the type of the deferred operation
+	Operation DisruptiveOperation `json:"operation"`
+	// Message specifies additional information about the deferred operation, like the requested version
+	// +optional
+	Message string `json:"message,omitempty"`
+	// DeferredSince specifies the time when the operation was deferred for the first time
+	// +optional
+	DeferredSince metav1.Time `json:"deferredSince,omitempty"`
+	// ScheduledAt specifies the start of the maintenance window in which the operation is going to be started
+	// +optional
+	ScheduledAt *metav1.Time `json:"scheduledAt,omitempty"`
+}
@@ -0,0 +1,169 @@
+package v1
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceWindowValidate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		window        MaintenanceWindow
+		expectedError string
+	}{
+		{
+			name:   "Valid window",
+			window: MaintenanceWindow{Days: []Weekday{Saturday, Sunday}, StartTime: "02:00", EndTime: "04:30", TimeZone: "Europe/Berlin"},
+		},
+		{
+			name:   "Valid window crossing midnight",
+			window: MaintenanceWindow{StartTime: "23:00", EndTime: "01:00"},
+		},
+		{
+			name:          "Invalid start time",
+			window:        MaintenanceWindow{StartTime: "25:00", EndTime: "01:00"},
+			expectedError: `.spec.maintenanceWindows[0].startTime: invalid time "25:00", expected HH:MM`,
+		},
+		{
+			name:          "Invalid end time",
+			window:        MaintenanceWindow{StartTime: "01:00", EndTime: "1am"},
+			expectedError: `.spec.maintenanceWindows[0].endTime: invalid time "1am", expected HH:MM`,
+		},
+		{
+			name:          "Empty window",
+			window:        MaintenanceWindow{StartTime: "01:00", EndTime: "01:00"},
+			expectedError: ".spec.maintenanceWindows[0]: startTime and endTime must differ",
+		},
+		{
+			name:          "Unknown time zone",
+			window:        MaintenanceWindow{StartTime: "01:00", EndTime: "02:00", TimeZone: "Mars/Olympus"},
+			expectedError: ".spec.maintenanceWindows[0].timeZone error: unknown time zone Mars/Olympus",
+		},
+		{
+			name:          "Unknown weekday",
+			window:        MaintenanceWindow{Days: []Weekday{"Caturday"}, StartTime: "01:00", EndTime: "02:00"},
+			expectedError: `.spec.maintenanceWindows[0].days error: invalid weekday "Caturday"`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.window.Validate(".spec.maintenanceWindows[0]")
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindowNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	// 2024-01-03 is a Wednesday
+	wednesday := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 3, hour, minute, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		name          string
+		window        MaintenanceWindow
+		now           time.Time
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			name:          "Before the window of today",
+			window:        MaintenanceWindow{StartTime: "02:00", EndTime: "04:00"},
+			now:           wednesday(1, 0),
+			expectedStart: wednesday(2, 0),
+			expectedEnd:   wednesday(4, 0),
+		},
+		{
+			name:          "In the window of today",
+			window:        MaintenanceWindow{StartTime: "02:00", EndTime: "04:00"},
+			now:           wednesday(2, 0),
+			expectedStart: wednesday(2, 0),
+			expectedEnd:   wednesday(4, 0),
+		},
+		{
+			name:          "At the end of the window of today",
+			window:        MaintenanceWindow{StartTime: "02:00", EndTime: "04:00"},
+			now:           wednesday(4, 0),
+			expectedStart: wednesday(2, 0).AddDate(0, 0, 1),
+			expectedEnd:   wednesday(4, 0).AddDate(0, 0, 1),
+		},
+		{
+			name:          "In a window which started yesterday",
+			window:        MaintenanceWindow{StartTime: "23:00", EndTime: "01:00"},
+			now:           wednesday(0, 30),
+			expectedStart: wednesday(23, 0).AddDate(0, 0, -1),
+			expectedEnd:   wednesday(1, 0),
+		},
+		{
+			name:          "Next window on a later weekday",
+			window:        MaintenanceWindow{Days: []Weekday{Saturday}, StartTime: "02:00", EndTime: "04:00"},
+			now:           wednesday(12, 0),
+			expectedStart: wednesday(2, 0).AddDate(0, 0, 3),
+			expectedEnd:   wednesday(4, 0).AddDate(0, 0, 3),
+		},
+		{
+			name:          "Next window on the same weekday next week",
+			window:        MaintenanceWindow{Days: []Weekday{Wednesday}, StartTime: "02:00", EndTime: "04:00"},
+			now:           wednesday(12, 0),
+			expectedStart: wednesday(2, 0).AddDate(0, 0, 7),
+			expectedEnd:   wednesday(4, 0).AddDate(0, 0, 7),
+		},
+		{
+			name:          "Window in another time zone",
+			window:        MaintenanceWindow{StartTime: "02:00", EndTime: "04:00", TimeZone: "Europe/Berlin"},
+			now:           wednesday(0, 0),
+			expectedStart: time.Date(2024, 1, 3, 2, 0, 0, 0, berlin),
+			expectedEnd:   time.Date(2024, 1, 3, 4, 0, 0, 0, berlin),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, ok := tt.window.Next(tt.now)
+			require.True(t, ok)
+			assert.True(t, tt.expectedStart.Equal(start), "expected start %s, got %s", tt.expectedStart, start)
+			assert.True(t, tt.expectedEnd.Equal(end), "expected end %s, got %s", tt.expectedEnd, end)
+		})
+	}
+}
+
+func TestInMaintenanceWindow(t *testing.T) {
+	now := time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)
+	nightly := MaintenanceWindow{StartTime: "02:00", EndTime: "04:00"}
+	noon := MaintenanceWindow{StartTime: "11:00", EndTime: "13:00"}
+
+	assert.True(t, QdrantClusterSpec{}.InMaintenanceWindow(now), "no windows means always allowed")
+	assert.False(t, QdrantClusterSpec{MaintenanceWindows: []MaintenanceWindow{nightly}}.InMaintenanceWindow(now))
+	assert.True(t, QdrantClusterSpec{MaintenanceWindows: []MaintenanceWindow{nightly, noon}}.InMaintenanceWindow(now))
+	assert.True(t, QdrantClusterSpec{
+		MaintenanceWindows:   []MaintenanceWindow{nightly},
+		EmergencyMaintenance: true,
+	}.InMaintenanceWindow(now), "emergency maintenance overrides the windows")
+}
+
+func TestNextWindow(t *testing.T) {
+	now := time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)
+
+	_, _, ok := QdrantClusterSpec{}.NextWindow(now)
+	assert.False(t, ok)
+
+	spec := QdrantClusterSpec{MaintenanceWindows: []MaintenanceWindow{
+		{StartTime: "02:00", EndTime: "04:00"},
+		{StartTime: "18:00", EndTime: "19:00"},
+	}}
+	start, end, ok := spec.NextWindow(now)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 1, 3, 18, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2024, 1, 3, 19, 0, 0, 0, time.UTC), end)
+}
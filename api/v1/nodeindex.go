@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"slices"
+)
+
+// AllocateNodeIndex allocates a new node index, following the contract documented at AvailableNodeIndexes:
+// the index is always above every index allocated before, so indexes are never re-used or compacted.
+// The index is added to AvailableNodeIndexes and recorded in LastAllocatedNodeIndex.
+func (s *QdrantClusterStatus) AllocateNodeIndex() int {
+	next := 0
+	if last, found := s.highestNodeIndex(); found {
+		next = last + 1
+	}
+	s.AvailableNodeIndexes = append(s.AvailableNodeIndexes, next)
+	s.AvailableNodes = len(s.AvailableNodeIndexes)
+	s.LastAllocatedNodeIndex = &next
+	return next
+}
+
+// FreeNodeIndex removes the node index from AvailableNodeIndexes and DeleteInProgessNodeIndexes,
+// once the node (including its PVCs and consensus peer) is fully dropped.
+// The index won't be allocated again.
+// Returns false if the index isn't allocated.
+func (s *QdrantClusterStatus) FreeNodeIndex(idx int) bool {
+	i := slices.Index(s.AvailableNodeIndexes, idx)
+	if i < 0 {
+		return false
+	}
+	// Make sure the index isn't re-used, if LastAllocatedNodeIndex wasn't set before
+	if last, _ := s.highestNodeIndex(); s.LastAllocatedNodeIndex == nil || *s.LastAllocatedNodeIndex < last {
+		s.LastAllocatedNodeIndex = &last
+	}
+	s.AvailableNodeIndexes = slices.Delete(s.AvailableNodeIndexes, i, i+1)
+	s.AvailableNodes = len(s.AvailableNodeIndexes)
+	s.DeleteInProgessNodeIndexes = slices.DeleteFunc(s.DeleteInProgessNodeIndexes, func(i int) bool { return i == idx })
+	if s.ScaleDownNodeIndex != nil && *s.ScaleDownNodeIndex == idx {
+		s.ScaleDownNodeIndex = nil
+	}
+	return true
+}
+
+// GetScaleDownNodeIndex returns the node index to remove in a scale down:
+// the ScaleDownNodeIndex if set, otherwise the last index in AvailableNodeIndexes.
+// Indexes which are already in progress of deleting are skipped.
+// Returns false if there is no node to remove.
+func (s *QdrantClusterStatus) GetScaleDownNodeIndex() (int, bool) {
+	if s == nil {
+		return 0, false
+	}
+	if idx := s.ScaleDownNodeIndex; idx != nil &&
+		slices.Contains(s.AvailableNodeIndexes, *idx) && !slices.Contains(s.DeleteInProgessNodeIndexes, *idx) {
+		return *idx, true
+	}
+	for i := len(s.AvailableNodeIndexes) - 1; i >= 0; i-- {
+		if idx := s.AvailableNodeIndexes[i]; !slices.Contains(s.DeleteInProgessNodeIndexes, idx) {
+			return idx, true
+		}
+	}
+	return 0, false
+}
+
+// highestNodeIndex returns the highest node index allocated so far.
+func (s *QdrantClusterStatus) highestNodeIndex() (int, bool) {
+	result, found := 0, false
+	if s.LastAllocatedNodeIndex != nil {
+		result, found = *s.LastAllocatedNodeIndex, true
+	}
+	for _, idx := range slices.Concat(s.AvailableNodeIndexes, s.DeleteInProgessNodeIndexes) {
+		if !found || idx > result {
+			result, found = idx, true
+		}
+	}
+	return result, found
+}
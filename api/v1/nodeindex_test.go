@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"slices"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func TestAllocateNodeIndex(t *testing.T) {
+	var status QdrantClusterStatus
+	assert.Equal(t, 0, status.AllocateNodeIndex())
+	assert.Equal(t, 1, status.AllocateNodeIndex())
+	assert.Equal(t, 2, status.AllocateNodeIndex())
+	assert.Equal(t, 3, status.AvailableNodes)
+
+	assert.True(t, status.FreeNodeIndex(2))
+	assert.False(t, status.FreeNodeIndex(2))
+	assert.Equal(t, 3, status.AllocateNodeIndex(), "freed indexes are not re-used")
+	assert.Equal(t, []int{0, 1, 3}, status.AvailableNodeIndexes)
+
+	// Status written before LastAllocatedNodeIndex was introduced
+	legacy := QdrantClusterStatus{AvailableNodeIndexes: []int{4, 7}, DeleteInProgessNodeIndexes: []int{7}}
+	assert.True(t, legacy.FreeNodeIndex(7))
+	assert.Empty(t, legacy.DeleteInProgessNodeIndexes)
+	assert.Equal(t, 8, legacy.AllocateNodeIndex())
+}
+
+func TestGetScaleDownNodeIndex(t *testing.T) {
+	testCases := []struct {
+		name          string
+		status        *QdrantClusterStatus
+		expected      int
+		expectedFound bool
+	}{
+		{name: "Nil status"},
+		{name: "No nodes", status: &QdrantClusterStatus{}},
+		{name: "Last index", status: &QdrantClusterStatus{AvailableNodeIndexes: []int{0, 3, 2}}, expected: 2, expectedFound: true},
+		{name: "Explicit index", status: &QdrantClusterStatus{AvailableNodeIndexes: []int{0, 3, 2}, ScaleDownNodeIndex: ptr.To(3)}, expected: 3, expectedFound: true},
+		{name: "Unknown explicit index", status: &QdrantClusterStatus{AvailableNodeIndexes: []int{0, 3, 2}, ScaleDownNodeIndex: ptr.To(5)}, expected: 2, expectedFound: true},
+		{name: "Skip deleting index", status: &QdrantClusterStatus{AvailableNodeIndexes: []int{0, 3, 2}, DeleteInProgessNodeIndexes: []int{2}}, expected: 3, expectedFound: true},
+		{name: "All deleting", status: &QdrantClusterStatus{AvailableNodeIndexes: []int{2}, DeleteInProgessNodeIndexes: []int{2}}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			idx, found := tt.status.GetScaleDownNodeIndex()
+			assert.Equal(t, tt.expectedFound, found)
+			assert.Equal(t, tt.expected, idx)
+		})
+	}
+}
+
+// TestNodeIndexesAreNeverReused applies random sequences of allocations and frees,
+// and checks an index is never handed out twice.
+func TestNodeIndexesAreNeverReused(t *testing.T) {
+	property := func(ops []uint8) bool {
+		var status QdrantClusterStatus
+		allocated := map[int]bool{}
+		for _, op := range ops {
+			if op%3 != 0 || len(status.AvailableNodeIndexes) == 0 {
+				idx := status.AllocateNodeIndex()
+				if allocated[idx] {
+					return false
+				}
+				allocated[idx] = true
+				continue
+			}
+			// Free a random node, or the scale down candidate
+			idx := status.AvailableNodeIndexes[int(op)%len(status.AvailableNodeIndexes)]
+			if op%2 == 0 {
+				idx, _ = status.GetScaleDownNodeIndex()
+			}
+			if !status.FreeNodeIndex(idx) {
+				return false
+			}
+		}
+		return status.AvailableNodes == len(status.AvailableNodeIndexes) && !hasDuplicates(status.AvailableNodeIndexes)
+	}
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 1000}))
+}
+
+func hasDuplicates(indexes []int) bool {
+	sorted := slices.Clone(indexes)
+	slices.Sort(sorted)
+	return len(slices.Compact(sorted)) != len(indexes)
+}
@@ -0,0 +1,197 @@
+package v1
+
+import (
+	"fmt"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// NodeOverride specifies settings of a single node which override the settings of the cluster and its node pool
+type NodeOverride struct {
+	// NodeIndex specifies the index of the node (see Status.AvailableNodeIndexes)
+	// +kubebuilder:validation:Minimum=0
+	NodeIndex int `json:"nodeIndex"`
+	// Resources specifies the resources of the node.
+	// Only the fields which are set override the resources of the cluster (or node pool).
+	// The storage can't be less than the storage of the cluster (or node pool), because volumes can't shrink.
+	// +optional
+	Resources *Resources `json:"resources,omitempty"`
+	// NodeSelector specifies additional node selector labels for the node,
+	// which are merged into the node selector of the cluster (or node pool).
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Zone specifies the zone to pin the node to, using the topology.kubernetes.io/zone label.
+	// +optional
+	Zone *string `json:"zone,omitempty"`
+}
+
+// NodeSpec specifies the effective settings of a single node,
+// after merging the settings of the cluster, its node pool and its NodeOverride.
+type NodeSpec struct {
+	// NodeIndex specifies the index of the node
+	NodeIndex int `json:"nodeIndex"`
+	// NodePool specifies the name of the node pool of the node
+	NodePool string `json:"nodePool"`
+	// Resources specifies the resources of the node
+	Resources Resources `json:"resources"`
+	// NodeSelector specifies the node selector of the node
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations specifies the tolerations of the node
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// GPU specifies the GPU configuration of the node
+	// +optional
+	GPU *GPU `json:"gpu,omitempty"`
+	// StorageClassNames specifies the storage class names of the node
+	// +optional
+	StorageClassNames *StorageClassNames `json:"storageClassNames,omitempty"`
+}
+
+// validateNodeOverrides validates the node overrides, and the effective resources of the overridden nodes
+// in every node pool they can belong to (see getNodeSpecs).
+func (s QdrantClusterSpec) validateNodeOverrides() error {
+	indexes := make(map[int]bool, len(s.NodeOverrides))
+	for i, o := range s.NodeOverrides {
+		base := fmt.Sprintf(".spec.nodeOverrides[%d]", i)
+		if indexes[o.NodeIndex] {
+			return fmt.Errorf("%s: duplicate node index %d", base, o.NodeIndex)
+		}
+		indexes[o.NodeIndex] = true
+		if o.Resources == nil {
+			continue
+		}
+		if err := validatePartialResources(base+".resources", o.Resources); err != nil {
+			return err
+		}
+		for _, node := range s.getNodeSpecs(o.NodeIndex) {
+			pool, _ := s.GetNodePool(node.NodePool)
+			if err := validateNodeResources(base+".resources", node, pool.Resources.Storage); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateNodeResources validates the effective resources of a node in a node pool with the given storage:
+// the requests can't exceed the limits, and the storage can't be less than the storage of the pool, because volumes can't shrink.
+func validateNodeResources(base string, node NodeSpec, poolStorage string) error {
+	r := node.Resources
+	if err := r.Validate(base); err != nil {
+		return err
+	}
+	for _, field := range []struct{ name, request, limit string }{
+		{"cpu", r.GetRequestCPU(), r.CPU},
+		{"memory", r.GetRequestMemory(), r.Memory},
+	} {
+		if request, limit := resource.MustParse(field.request), resource.MustParse(field.limit); request.Cmp(limit) > 0 {
+			return fmt.Errorf("%s.requests.%s: %s is greater than the %s limit %s of node pool %s", base, field.name, field.request, field.name, field.limit, node.NodePool)
+		}
+	}
+	if storage, minimum := resource.MustParse(r.Storage), resource.MustParse(poolStorage); storage.Cmp(minimum) < 0 {
+		return fmt.Errorf("%s.storage: %s is less than the storage %s of node pool %s, volumes can't shrink", base, r.Storage, poolStorage, node.NodePool)
+	}
+	return nil
+}
+
+// validatePartialResources validates the fields which are set of resources overriding other resources
+func validatePartialResources(base string, r *Resources) error {
+	if r == nil {
+		return nil
+	}
+	for _, field := range []struct{ name, value string }{
+		{"cpu", r.CPU},
+		{"memory", r.Memory},
+		{"storage", r.Storage},
+		{"requests.cpu", r.Requests.CPU},
+		{"requests.memory", r.Requests.Memory},
+	} {
+		if field.value == "" {
+			continue
+		}
+		if _, err := resource.ParseQuantity(field.value); err != nil {
+			return fmt.Errorf("%s.%s error: %w", base, field.name, err)
+		}
+	}
+	return nil
+}
+
+// overrideResources sets the fields of target which are set in the given resources
+func overrideResources(target *Resources, r *Resources) {
+	if r == nil {
+		return
+	}
+	overrideString(&target.CPU, r.CPU)
+	overrideString(&target.Memory, r.Memory)
+	overrideString(&target.Storage, r.Storage)
+	overrideString(&target.Requests.CPU, r.Requests.CPU)
+	overrideString(&target.Requests.Memory, r.Requests.Memory)
+}
+
+// GetNodeOverride returns the override of the node with the given index, nil if not found.
+func (s QdrantClusterSpec) GetNodeOverride(idx int) *NodeOverride {
+	for i := range s.NodeOverrides {
+		if s.NodeOverrides[i].NodeIndex == idx {
+			return &s.NodeOverrides[i]
+		}
+	}
+	return nil
+}
+
+// GetNodeSpec returns the effective settings of the node with the given index in the given node pool
+// (empty for the default pool), by merging the NodeOverride of the node into the settings of the node pool.
+// Returns false if the node pool doesn't exist.
+func (s QdrantClusterSpec) GetNodeSpec(idx int, nodePool string) (NodeSpec, bool) {
+	pool, found := s.GetNodePool(nodePool)
+	if !found {
+		return NodeSpec{}, false
+	}
+	result := NodeSpec{
+		NodeIndex:         idx,
+		NodePool:          pool.Name,
+		Resources:         *pool.Resources,
+		NodeSelector:      maps.Clone(pool.NodeSelector),
+		Tolerations:       pool.Tolerations,
+		GPU:               pool.GPU,
+		StorageClassNames: pool.StorageClassNames,
+	}
+	o := s.GetNodeOverride(idx)
+	if o == nil {
+		return result, true
+	}
+	overrideResources(&result.Resources, o.Resources)
+	if len(o.NodeSelector) > 0 || o.Zone != nil {
+		if result.NodeSelector == nil {
+			result.NodeSelector = make(map[string]string)
+		}
+		maps.Copy(result.NodeSelector, o.NodeSelector)
+		if o.Zone != nil {
+			result.NodeSelector[corev1.LabelTopologyZone] = *o.Zone
+		}
+	}
+	return result, true
+}
+
+// getNodeSpecs returns the effective settings of the node with the given index
+// for every node pool the node can belong to, i.e. the pools with at least one node.
+func (s QdrantClusterSpec) getNodeSpecs(idx int) []NodeSpec {
+	var result []NodeSpec
+	for _, name := range s.GetNodePoolNames() {
+		if pool, _ := s.GetNodePool(name); pool.Size <= 0 {
+			continue
+		}
+		node, _ := s.GetNodeSpec(idx, name)
+		result = append(result, node)
+	}
+	return result
+}
+
+// overrideString sets target to value, if value is not empty
+func overrideString(target *string, value string) {
+	if value != "" {
+		*target = value
+	}
+}
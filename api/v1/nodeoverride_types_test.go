@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidateNodeOverrides(t *testing.T) {
+	resources := Resources{CPU: "1", Memory: "4Gi", Storage: "10Gi"}
+	testCases := []struct {
+		name          string
+		overrides     []NodeOverride
+		nodePools     []NodePool
+		expectedError string
+	}{
+		{name: "Valid", overrides: []NodeOverride{{NodeIndex: 1, Resources: &Resources{Memory: "8Gi"}}, {NodeIndex: 3, Zone: ptr.To("b")}}},
+		{
+			name:          "Duplicate index",
+			overrides:     []NodeOverride{{NodeIndex: 1, Zone: ptr.To("a")}, {NodeIndex: 1, Zone: ptr.To("b")}},
+			expectedError: ".spec.nodeOverrides[1]: duplicate node index 1",
+		},
+		{
+			name:          "Invalid memory",
+			overrides:     []NodeOverride{{NodeIndex: 1, Resources: &Resources{Memory: "lots"}}},
+			expectedError: ".spec.nodeOverrides[0].resources.memory error: quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'",
+		},
+		{
+			name:          "Request above limit",
+			overrides:     []NodeOverride{{NodeIndex: 1, Resources: &Resources{Requests: ResourceRequests{Memory: "8Gi"}}}},
+			expectedError: ".spec.nodeOverrides[0].resources.requests.memory: 8Gi is greater than the memory limit 4Gi of node pool default",
+		},
+		{
+			name:          "Valid in every node pool",
+			overrides:     []NodeOverride{{NodeIndex: 1, Resources: &Resources{Requests: ResourceRequests{CPU: "1500m"}, CPU: "2"}}},
+			nodePools:     []NodePool{{Name: "small", Size: 1, Resources: &Resources{CPU: "1", Memory: "2Gi", Storage: "10Gi"}}},
+			expectedError: "",
+		},
+		{
+			name:          "Limit below request of node pool",
+			overrides:     []NodeOverride{{NodeIndex: 1, Resources: &Resources{Memory: "3Gi"}}},
+			nodePools:     []NodePool{{Name: "small", Size: 1, Resources: &Resources{CPU: "1", Memory: "8Gi", Storage: "10Gi", Requests: ResourceRequests{Memory: "4Gi"}}}},
+			expectedError: ".spec.nodeOverrides[0].resources.requests.memory: 4Gi is greater than the memory limit 3Gi of node pool small",
+		},
+		{
+			name:      "Empty node pool is ignored",
+			overrides: []NodeOverride{{NodeIndex: 1, Resources: &Resources{Memory: "3Gi"}}},
+			nodePools: []NodePool{{Name: "small", Size: 0, Resources: &Resources{CPU: "1", Memory: "8Gi", Storage: "10Gi", Requests: ResourceRequests{Memory: "4Gi"}}}},
+		},
+		{
+			name:          "Storage below node pool",
+			overrides:     []NodeOverride{{NodeIndex: 1, Resources: &Resources{Storage: "20Gi"}}},
+			nodePools:     []NodePool{{Name: "large", Size: 1, Resources: &Resources{CPU: "1", Memory: "4Gi", Storage: "50Gi"}}},
+			expectedError: ".spec.nodeOverrides[0].resources.storage: 20Gi is less than the storage 50Gi of node pool large, volumes can't shrink",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := QdrantClusterSpec{Size: 3, Resources: resources, NodePools: tt.nodePools, NodeOverrides: tt.overrides}
+			err := spec.Validate()
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestGetNodeSpec(t *testing.T) {
+	spec := QdrantClusterSpec{
+		Size:         4,
+		Resources:    Resources{CPU: "1", Memory: "4Gi", Storage: "10Gi", Requests: ResourceRequests{Memory: "2Gi"}},
+		NodeSelector: map[string]string{"pool": "general"},
+		NodePools:    []NodePool{{Name: "gpu", Size: 1, GPU: &GPU{GPUType: GPUTypeNvidia}}},
+		NodeOverrides: []NodeOverride{
+			{NodeIndex: 1, Resources: &Resources{Memory: "16Gi", Requests: ResourceRequests{Memory: "8Gi"}}},
+			{NodeIndex: 3, Zone: ptr.To("zone-b"), NodeSelector: map[string]string{"disk": "ssd"}},
+		},
+	}
+
+	node, found := spec.GetNodeSpec(0, "")
+	require.True(t, found)
+	assert.Equal(t, NodeSpec{
+		NodeIndex:    0,
+		NodePool:     DefaultNodePoolName,
+		Resources:    spec.Resources,
+		NodeSelector: map[string]string{"pool": "general"},
+	}, node)
+
+	node, found = spec.GetNodeSpec(1, "")
+	require.True(t, found)
+	assert.Equal(t, Resources{CPU: "1", Memory: "16Gi", Storage: "10Gi", Requests: ResourceRequests{Memory: "8Gi"}}, node.Resources)
+
+	node, found = spec.GetNodeSpec(3, "gpu")
+	require.True(t, found)
+	assert.Equal(t, "gpu", node.NodePool)
+	assert.Equal(t, GPUTypeNvidia, node.GPU.GPUType)
+	assert.Equal(t, map[string]string{"pool": "general", "disk": "ssd", corev1.LabelTopologyZone: "zone-b"}, node.NodeSelector)
+	assert.Equal(t, map[string]string{"pool": "general"}, spec.NodeSelector, "the cluster settings are not modified")
+
+	_, found = spec.GetNodeSpec(0, "unknown")
+	assert.False(t, found)
+}
@@ -0,0 +1,154 @@
+package v1
+
+import (
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DefaultNodePoolName is the name of the pool of the nodes which are not part of one of the NodePools
+const DefaultNodePoolName = "default"
+
+// NodePool specifies a pool of nodes with their own resources, scheduling and storage classes.
+// Settings which are not set are taken from the cluster.
+type NodePool struct {
+	// Name specifies the name of the pool
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=20
+	Name string `json:"name"`
+	// Size specifies the desired number of nodes in the pool
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Size int `json:"size"`
+	// Resources specifies the resources to allocate for each node in the pool.
+	// If not set, the resources of the cluster are used.
+	// +optional
+	Resources *Resources `json:"resources,omitempty"`
+	// NodeSelector specifies the node selector for each node in the pool.
+	// If not set, the node selector of the cluster is used.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations specifies the tolerations for each node in the pool.
+	// If not set, the tolerations of the cluster are used.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// GPU specifies the GPU configuration for the nodes in the pool.
+	// If not set, the GPU configuration of the cluster is used.
+	// +optional
+	GPU *GPU `json:"gpu,omitempty"`
+	// StorageClassNames specifies the storage class names for the nodes in the pool.
+	// If not set, the storage class names of the cluster are used.
+	// +optional
+	StorageClassNames *StorageClassNames `json:"storageClassNames,omitempty"`
+}
+
+// NodePoolStatus specifies the status of a node pool
+type NodePoolStatus struct {
+	// Name specifies the name of the pool
+	Name string `json:"name"`
+	// Size specifies the desired number of nodes in the pool
+	Size int `json:"size"`
+	// AvailableNodes specifies the number of available nodes in the pool
+	// +optional
+	AvailableNodes int `json:"availableNodes,omitempty"`
+}
+
+// validateNodePools validates the node pools against the rest of the spec
+func (s QdrantClusterSpec) validateNodePools() error {
+	names := make(map[string]bool, len(s.NodePools))
+	for i, pool := range s.NodePools {
+		if pool.Name == DefaultNodePoolName {
+			return fmt.Errorf(".spec.nodePools[%d]: name %q is reserved", i, DefaultNodePoolName)
+		}
+		if names[pool.Name] {
+			return fmt.Errorf(".spec.nodePools[%d]: duplicate name %q", i, pool.Name)
+		}
+		names[pool.Name] = true
+		if pool.Resources != nil {
+			if err := pool.Resources.Validate(fmt.Sprintf(".spec.nodePools[%d].resources", i)); err != nil {
+				return err
+			}
+		}
+	}
+	if s.GetDefaultNodePoolSize() < 0 {
+		return fmt.Errorf(".spec.nodePools: the sum of the pool sizes can not be greater than size (%d)", s.Size)
+	}
+	return nil
+}
+
+// GetDefaultNodePoolSize returns the number of nodes which are not part of one of the NodePools.
+func (s QdrantClusterSpec) GetDefaultNodePoolSize() int {
+	result := s.Size
+	for _, pool := range s.NodePools {
+		result -= pool.Size
+	}
+	return result
+}
+
+// GetNodePool returns the effective settings of the pool with the given name,
+// with the settings which are not set in the pool taken from the cluster.
+// An empty name or DefaultNodePoolName returns the default pool.
+// Returns false if the pool doesn't exist.
+func (s QdrantClusterSpec) GetNodePool(name string) (NodePool, bool) {
+	result := NodePool{
+		Name:              DefaultNodePoolName,
+		Size:              s.GetDefaultNodePoolSize(),
+		Resources:         &s.Resources,
+		NodeSelector:      s.NodeSelector,
+		Tolerations:       s.Tolerations,
+		GPU:               s.GPU,
+		StorageClassNames: s.StorageClassNames,
+	}
+	if name == "" || name == DefaultNodePoolName {
+		return result, true
+	}
+	i := slices.IndexFunc(s.NodePools, func(p NodePool) bool { return p.Name == name })
+	if i < 0 {
+		return NodePool{}, false
+	}
+	pool := s.NodePools[i]
+	result.Name = pool.Name
+	result.Size = pool.Size
+	if pool.Resources != nil {
+		result.Resources = pool.Resources
+	}
+	if pool.NodeSelector != nil {
+		result.NodeSelector = pool.NodeSelector
+	}
+	if pool.Tolerations != nil {
+		result.Tolerations = pool.Tolerations
+	}
+	if pool.GPU != nil {
+		result.GPU = pool.GPU
+	}
+	if pool.StorageClassNames != nil {
+		result.StorageClassNames = pool.StorageClassNames
+	}
+	return result, true
+}
+
+// GetNodePoolNames returns the names of all pools, starting with the default pool.
+func (s QdrantClusterSpec) GetNodePoolNames() []string {
+	result := []string{DefaultNodePoolName}
+	for _, pool := range s.NodePools {
+		result = append(result, pool.Name)
+	}
+	return result
+}
+
+// UpdateNodePoolStatuses computes the status of all pools of the given spec from the status of the nodes.
+func (s *QdrantClusterStatus) UpdateNodePoolStatuses(spec QdrantClusterSpec) {
+	s.NodePools = nil
+	for _, name := range spec.GetNodePoolNames() {
+		pool, _ := spec.GetNodePool(name)
+		status := NodePoolStatus{Name: name, Size: pool.Size}
+		for _, node := range s.Nodes {
+			inPool := node.NodePool == name || (node.NodePool == "" && name == DefaultNodePoolName)
+			if inPool && node.State[corev1.PodReady] == corev1.ConditionTrue {
+				status.AvailableNodes++
+			}
+		}
+		s.NodePools = append(s.NodePools, status)
+	}
+}
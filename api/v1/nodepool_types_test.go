@@ -0,0 +1,113 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidateNodePools(t *testing.T) {
+	resources := Resources{CPU: "1", Memory: "4Gi", Storage: "10Gi"}
+	testCases := []struct {
+		name          string
+		size          int
+		pools         []NodePool
+		expectedError string
+	}{
+		{name: "No pools", size: 3},
+		{name: "Valid pools", size: 5, pools: []NodePool{{Name: "memory", Size: 2}, {Name: "gpu", Size: 1, GPU: &GPU{GPUType: GPUTypeNvidia}}}},
+		{name: "Only pools", size: 2, pools: []NodePool{{Name: "memory", Size: 2}}},
+		{
+			name:          "Pools exceed size",
+			size:          2,
+			pools:         []NodePool{{Name: "memory", Size: 2}, {Name: "gpu", Size: 1}},
+			expectedError: ".spec.nodePools: the sum of the pool sizes can not be greater than size (2)",
+		},
+		{
+			name:          "Duplicate name",
+			size:          5,
+			pools:         []NodePool{{Name: "memory", Size: 1}, {Name: "memory", Size: 1}},
+			expectedError: `.spec.nodePools[1]: duplicate name "memory"`,
+		},
+		{
+			name:          "Reserved name",
+			size:          5,
+			pools:         []NodePool{{Name: DefaultNodePoolName, Size: 1}},
+			expectedError: `.spec.nodePools[0]: name "default" is reserved`,
+		},
+		{
+			name:          "Invalid resources",
+			size:          5,
+			pools:         []NodePool{{Name: "memory", Size: 1, Resources: &Resources{CPU: "x", Memory: "1Gi", Storage: "1Gi"}}},
+			expectedError: ".spec.nodePools[0].resources.CPU error: quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := QdrantClusterSpec{Size: tt.size, Resources: resources, NodePools: tt.pools}
+			err := spec.Validate()
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestGetNodePool(t *testing.T) {
+	memory := Resources{CPU: "2", Memory: "64Gi", Storage: "100Gi"}
+	spec := QdrantClusterSpec{
+		Size:              5,
+		Resources:         Resources{CPU: "1", Memory: "4Gi", Storage: "10Gi"},
+		NodeSelector:      map[string]string{"pool": "general"},
+		StorageClassNames: &StorageClassNames{DB: ptr.To("fast")},
+		NodePools: []NodePool{
+			{Name: "memory", Size: 2, Resources: &memory},
+			{Name: "gpu", Size: 1, GPU: &GPU{GPUType: GPUTypeNvidia}, NodeSelector: map[string]string{"pool": "gpu"}},
+		},
+	}
+	assert.Equal(t, 2, spec.GetDefaultNodePoolSize())
+	assert.Equal(t, []string{DefaultNodePoolName, "memory", "gpu"}, spec.GetNodePoolNames())
+
+	pool, found := spec.GetNodePool("")
+	require.True(t, found)
+	assert.Equal(t, DefaultNodePoolName, pool.Name)
+	assert.Equal(t, 2, pool.Size)
+	assert.Equal(t, "4Gi", pool.Resources.Memory)
+
+	pool, found = spec.GetNodePool("memory")
+	require.True(t, found)
+	assert.Equal(t, "64Gi", pool.Resources.Memory)
+	assert.Equal(t, "general", pool.NodeSelector["pool"], "inherited from the cluster")
+	assert.Equal(t, "fast", *pool.StorageClassNames.DB, "inherited from the cluster")
+	assert.Nil(t, pool.GPU)
+
+	pool, found = spec.GetNodePool("gpu")
+	require.True(t, found)
+	assert.Equal(t, "gpu", pool.NodeSelector["pool"])
+	assert.Equal(t, GPUTypeNvidia, pool.GPU.GPUType)
+	assert.Equal(t, "4Gi", pool.Resources.Memory, "inherited from the cluster")
+
+	_, found = spec.GetNodePool("unknown")
+	assert.False(t, found)
+}
+
+func TestUpdateNodePoolStatuses(t *testing.T) {
+	spec := QdrantClusterSpec{Size: 3, NodePools: []NodePool{{Name: "gpu", Size: 1}}}
+	ready := map[corev1.PodConditionType]corev1.ConditionStatus{corev1.PodReady: corev1.ConditionTrue}
+	status := QdrantClusterStatus{Nodes: map[string]NodeStatus{
+		"node-0": {State: ready},
+		"node-1": {},
+		"node-2": {NodePool: "gpu", State: ready},
+	}}
+	status.UpdateNodePoolStatuses(spec)
+	assert.Equal(t, []NodePoolStatus{
+		{Name: DefaultNodePoolName, Size: 2, AvailableNodes: 1},
+		{Name: "gpu", Size: 1, AvailableNodes: 1},
+	}, status.NodePools)
+}
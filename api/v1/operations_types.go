@@ -0,0 +1,285 @@
+package v1
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OperationType specifies the type of operation requested on a cluster
+// +kubebuilder:validation:Enum=Restart;OnDemandReplicationRestart;RecreateNode;Reinit;BootstrapNode
+type OperationType string
+
+//goland:noinspection GoUnusedConst
+const (
+	// OperationRestart restarts all nodes, see RestartedAtAnnotationKey
+	OperationRestart OperationType = "Restart"
+	// OperationOnDemandReplicationRestart executes an on-demand replication restart, see OnDemandReplicationRestartAnnotationKey
+	OperationOnDemandReplicationRestart OperationType = "OnDemandReplicationRestart"
+	// OperationRecreateNode recreates the nodes in NodeIndexes, see RecreateNodeAnnotationKey
+	OperationRecreateNode OperationType = "RecreateNode"
+	// OperationReinit reinitializes the cluster, see ReinitAnnotationKey
+	OperationReinit OperationType = "Reinit"
+	// OperationBootstrapNode uses the node in NodeIndexes as bootstrap node, see BootstrapNodeAnnotationKey
+	OperationBootstrapNode OperationType = "BootstrapNode"
+)
+
+// OperationRequest specifies an operation to execute on the cluster
+type OperationRequest struct {
+	// Id specifies the unique identifier of the request.
+	// An operation is executed only once per id, so re-applying the same request is a no-op.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Id string `json:"id"`
+	// Type specifies the type of the operation
+	Type OperationType `json:"type"`
+	// NodeIndexes specifies the indexes of the nodes the operation applies to.
+	// Required for RecreateNode (one or more) and BootstrapNode (exactly one), not allowed otherwise.
+	// +optional
+	NodeIndexes []int `json:"nodeIndexes,omitempty"`
+	// Reason specifies why the operation is requested, used in Events.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// OperationPhase specifies the phase of a requested operation
+type OperationPhase string
+
+//goland:noinspection GoUnusedConst
+const (
+	OperationPending   OperationPhase = "Pending"
+	OperationRunning   OperationPhase = "Running"
+	OperationSucceeded OperationPhase = "Succeeded"
+	OperationFailed    OperationPhase = "Failed"
+)
+
+// OperationStatus specifies the status of a requested operation
+type OperationStatus struct {
+	// Id specifies the identifier of the request (see OperationRequest.Id)
+	Id string `json:"id"`
+	// Type specifies the type of the operation
+	Type OperationType `json:"type"`
+	// Phase specifies the phase of the operation
+	Phase OperationPhase `json:"phase"`
+	// Message specifies details about the phase of the operation
+	// +optional
+	Message string `json:"message,omitempty"`
+	// StartTime specifies when the operation started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime specifies when the operation succeeded or failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// IsCompleted returns true if the operation succeeded or failed
+func (s OperationStatus) IsCompleted() bool {
+	return s.Phase == OperationSucceeded || s.Phase == OperationFailed
+}
+
+// validateOperations validates the operation requests, without knowledge of the status (see ValidateOperations)
+func validateOperations(operations []OperationRequest) error {
+	ids := make(map[string]bool, len(operations))
+	for i, op := range operations {
+		if ids[op.Id] {
+			return fmt.Errorf(".spec.operations[%d]: duplicate id %q", i, op.Id)
+		}
+		ids[op.Id] = true
+		switch op.Type {
+		case OperationRecreateNode:
+			if len(op.NodeIndexes) == 0 {
+				return fmt.Errorf(".spec.operations[%d]: nodeIndexes is required for %s", i, op.Type)
+			}
+		case OperationBootstrapNode:
+			if len(op.NodeIndexes) != 1 {
+				return fmt.Errorf(".spec.operations[%d]: exactly one node index is required for %s", i, op.Type)
+			}
+		default:
+			if len(op.NodeIndexes) > 0 {
+				return fmt.Errorf(".spec.operations[%d]: nodeIndexes is not allowed for %s", i, op.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateOperations validates the time based operation annotations, the pending operation requests and the nodes to decommission against the status,
+// e.g. the node indexes should be part of AvailableNodeIndexes.
+func (qc *QdrantCluster) ValidateOperations() error {
+	if _, err := qc.GetRestartedAt(); err != nil {
+		return err
+	}
+	if _, err := qc.GetOnDemandReplicationRestart(); err != nil {
+		return err
+	}
+	for _, idx := range qc.Spec.DecommissionNodeIndexes {
+		if !slices.Contains(qc.Status.AvailableNodeIndexes, idx) && qc.Status.GetDecommissionStatus(idx) == nil {
+			return fmt.Errorf(".spec.decommissionNodeIndexes: node index %d doesn't exist", idx)
+		}
+	}
+	for i, op := range qc.Spec.Operations {
+		if !qc.isOperationPending(op) {
+			continue
+		}
+		for _, idx := range op.NodeIndexes {
+			if !slices.Contains(qc.Status.AvailableNodeIndexes, idx) {
+				return fmt.Errorf(".spec.operations[%d]: node index %d doesn't exist", i, idx)
+			}
+		}
+	}
+	return nil
+}
+
+// GetPendingOperations returns the operation requests which are not completed yet.
+func (qc *QdrantCluster) GetPendingOperations() []OperationRequest {
+	var result []OperationRequest
+	for _, op := range qc.Spec.Operations {
+		if qc.isOperationPending(op) {
+			result = append(result, op)
+		}
+	}
+	return result
+}
+
+// isOperationPending returns true if the operation request is not completed yet.
+func (qc *QdrantCluster) isOperationPending(op OperationRequest) bool {
+	s := qc.Status.GetOperationStatus(op.Id)
+	return s == nil || !s.IsCompleted()
+}
+
+// GetOperationStatus returns the status of the operation with the given id, nil if not found.
+func (s *QdrantClusterStatus) GetOperationStatus(id string) *OperationStatus {
+	if s == nil {
+		return nil
+	}
+	for i := range s.Operations {
+		if s.Operations[i].Id == id {
+			return &s.Operations[i]
+		}
+	}
+	return nil
+}
+
+// SetOperationStatus sets the phase of the given operation.
+// The StartTime is set when the operation leaves Pending, the CompletionTime when it completes.
+// The statuses of operations which are no longer requested in Spec.Operations are dropped (see PruneOperationStatuses).
+func (qc *QdrantCluster) SetOperationStatus(op OperationRequest, phase OperationPhase, message string, now time.Time) {
+	qc.Status.PruneOperationStatuses(qc.Spec.Operations)
+	s := &qc.Status
+	status := s.GetOperationStatus(op.Id)
+	if status == nil {
+		s.Operations = append(s.Operations, OperationStatus{Id: op.Id, Type: op.Type})
+		status = &s.Operations[len(s.Operations)-1]
+	}
+	status.Phase = phase
+	status.Message = message
+	if phase != OperationPending && status.StartTime == nil {
+		status.StartTime = &metav1.Time{Time: now}
+	}
+	if status.IsCompleted() && status.CompletionTime == nil {
+		status.CompletionTime = &metav1.Time{Time: now}
+	}
+}
+
+// PruneOperationStatuses drops the statuses of the operations which are not in the given operation requests,
+// so the statuses don't grow beyond the requested operations.
+// Note that an operation whose request is removed is executed again if it's requested again with the same id.
+func (s *QdrantClusterStatus) PruneOperationStatuses(operations []OperationRequest) {
+	if s == nil {
+		return
+	}
+	s.Operations = slices.DeleteFunc(s.Operations, func(status OperationStatus) bool {
+		return !slices.ContainsFunc(operations, func(op OperationRequest) bool { return op.Id == status.Id })
+	})
+}
+
+// SetRestartedAt requests a restart of the cluster, see RestartedAtAnnotationKey.
+func (qc *QdrantCluster) SetRestartedAt(t time.Time) {
+	setAnnotation(qc, RestartedAtAnnotationKey, t.UTC().Format(time.RFC3339))
+}
+
+// GetRestartedAt returns the requested restart time, nil if no restart is requested.
+func (qc *QdrantCluster) GetRestartedAt() (*time.Time, error) {
+	return getTimeAnnotation(qc, RestartedAtAnnotationKey)
+}
+
+// SetOnDemandReplicationRestart requests an on-demand replication restart, see OnDemandReplicationRestartAnnotationKey.
+func (qc *QdrantCluster) SetOnDemandReplicationRestart(t time.Time) {
+	setAnnotation(qc, OnDemandReplicationRestartAnnotationKey, t.UTC().Format(time.RFC3339))
+}
+
+// GetOnDemandReplicationRestart returns the requested on-demand replication restart time, nil if not requested.
+func (qc *QdrantCluster) GetOnDemandReplicationRestart() (*time.Time, error) {
+	return getTimeAnnotation(qc, OnDemandReplicationRestartAnnotationKey)
+}
+
+// SetReinit requests the reinitialization of the cluster for the given reason, see ReinitAnnotationKey.
+func (qc *QdrantCluster) SetReinit(reason string) {
+	setAnnotation(qc, ReinitAnnotationKey, reason)
+}
+
+// GetReinit returns the reason of the reinitialization and true if reinitialization is requested.
+func (qc *QdrantCluster) GetReinit() (string, bool) {
+	reason, found := qc.GetAnnotations()[ReinitAnnotationKey]
+	return reason, found
+}
+
+// SetBootstrapNode explicitly specifies the bootstrap node, see BootstrapNodeAnnotationKey.
+func (qc *QdrantCluster) SetBootstrapNode(node string) error {
+	if node == "" {
+		return fmt.Errorf("annotation %s error: node can not be empty", BootstrapNodeAnnotationKey)
+	}
+	setAnnotation(qc, BootstrapNodeAnnotationKey, node)
+	return nil
+}
+
+// GetBootstrapNode returns the explicitly specified bootstrap node and true if specified.
+// The value is interpreted by the operator, so it's returned as is.
+func (qc *QdrantCluster) GetBootstrapNode() (string, bool) {
+	node, found := qc.GetAnnotations()[BootstrapNodeAnnotationKey]
+	return node, found
+}
+
+// SetRecreateNode requests the recreation of the node of the given pod for the given reason, see RecreateNodeAnnotationKey.
+func SetRecreateNode(pod metav1.Object, reason string) error {
+	if reason == "" {
+		return fmt.Errorf("annotation %s error: reason can not be empty", RecreateNodeAnnotationKey)
+	}
+	setAnnotation(pod, RecreateNodeAnnotationKey, reason)
+	return nil
+}
+
+// GetRecreateNode returns the reason and true if the recreation of the node of the given pod is requested.
+// Returns an error if the annotation is set without a value.
+func GetRecreateNode(pod metav1.Object) (string, bool, error) {
+	reason, found := pod.GetAnnotations()[RecreateNodeAnnotationKey]
+	if found && reason == "" {
+		return "", false, fmt.Errorf("annotation %s error: value can not be empty", RecreateNodeAnnotationKey)
+	}
+	return reason, found, nil
+}
+
+// setAnnotation sets the annotation on the object
+func setAnnotation(obj metav1.Object, key, value string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[key] = value
+	obj.SetAnnotations(annotations)
+}
+
+// getTimeAnnotation parses the RFC3339 formatted annotation of the object, nil if not set.
+func getTimeAnnotation(obj metav1.Object, key string) (*time.Time, error) {
+	value, found := obj.GetAnnotations()[key]
+	if !found {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("annotation %s error: invalid RFC3339 date %q", key, value)
+	}
+	return &t, nil
+}
@@ -0,0 +1,175 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOperationAnnotations(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	qc := &QdrantCluster{Status: QdrantClusterStatus{AvailableNodeIndexes: []int{0, 2}}}
+
+	restartedAt, err := qc.GetRestartedAt()
+	require.NoError(t, err)
+	assert.Nil(t, restartedAt)
+	qc.SetRestartedAt(now)
+	assert.Equal(t, "2024-01-01T12:00:00Z", qc.Annotations[RestartedAtAnnotationKey])
+	restartedAt, err = qc.GetRestartedAt()
+	require.NoError(t, err)
+	assert.Equal(t, now, *restartedAt)
+
+	qc.SetOnDemandReplicationRestart(now)
+	odrr, err := qc.GetOnDemandReplicationRestart()
+	require.NoError(t, err)
+	assert.Equal(t, now, *odrr)
+
+	_, found := qc.GetReinit()
+	assert.False(t, found)
+	qc.SetReinit("lost consensus")
+	reason, found := qc.GetReinit()
+	assert.True(t, found)
+	assert.Equal(t, "lost consensus", reason)
+
+	_, found = qc.GetBootstrapNode()
+	assert.False(t, found)
+	assert.EqualError(t, qc.SetBootstrapNode(""), "annotation operator.qdrant.com/bootstrap-node error: node can not be empty")
+	// The value written by the operator tooling is kept as is
+	qc.Annotations[BootstrapNodeAnnotationKey] = "qdrant-node-0"
+	node, found := qc.GetBootstrapNode()
+	assert.True(t, found)
+	assert.Equal(t, "qdrant-node-0", node)
+	require.NoError(t, qc.SetBootstrapNode("2"))
+	node, _ = qc.GetBootstrapNode()
+	assert.Equal(t, "2", node)
+	require.NoError(t, qc.ValidateOperations())
+
+	qc.Annotations[RestartedAtAnnotationKey] = "yesterday"
+	assert.EqualError(t, qc.ValidateOperations(), `annotation restartedAt error: invalid RFC3339 date "yesterday"`)
+}
+
+func TestRecreateNodeAnnotation(t *testing.T) {
+	pod := &corev1.Pod{}
+	_, found, err := GetRecreateNode(pod)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	assert.Error(t, SetRecreateNode(pod, ""))
+	require.NoError(t, SetRecreateNode(pod, "disk failure"))
+	reason, found, err := GetRecreateNode(pod)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "disk failure", reason)
+
+	pod.Annotations[RecreateNodeAnnotationKey] = ""
+	_, _, err = GetRecreateNode(pod)
+	assert.Error(t, err)
+}
+
+func TestValidateOperations(t *testing.T) {
+	testCases := []struct {
+		name          string
+		operations    []OperationRequest
+		status        QdrantClusterStatus
+		expectedError string
+	}{
+		{
+			name: "Valid operations",
+			operations: []OperationRequest{
+				{Id: "restart-1", Type: OperationRestart},
+				{Id: "recreate-1", Type: OperationRecreateNode, NodeIndexes: []int{0, 2}},
+			},
+			status: QdrantClusterStatus{AvailableNodeIndexes: []int{0, 1, 2}},
+		},
+		{
+			name:          "Duplicate id",
+			operations:    []OperationRequest{{Id: "restart", Type: OperationRestart}, {Id: "restart", Type: OperationReinit}},
+			expectedError: `.spec.operations[1]: duplicate id "restart"`,
+		},
+		{
+			name:          "Missing node indexes",
+			operations:    []OperationRequest{{Id: "recreate", Type: OperationRecreateNode}},
+			expectedError: ".spec.operations[0]: nodeIndexes is required for RecreateNode",
+		},
+		{
+			name:          "Multiple bootstrap nodes",
+			operations:    []OperationRequest{{Id: "bootstrap", Type: OperationBootstrapNode, NodeIndexes: []int{0, 1}}},
+			expectedError: ".spec.operations[0]: exactly one node index is required for BootstrapNode",
+		},
+		{
+			name:          "Unexpected node indexes",
+			operations:    []OperationRequest{{Id: "restart", Type: OperationRestart, NodeIndexes: []int{0}}},
+			expectedError: ".spec.operations[0]: nodeIndexes is not allowed for Restart",
+		},
+		{
+			name:          "Unknown node index",
+			operations:    []OperationRequest{{Id: "recreate", Type: OperationRecreateNode, NodeIndexes: []int{3}}},
+			status:        QdrantClusterStatus{AvailableNodeIndexes: []int{0, 1, 2}},
+			expectedError: ".spec.operations[0]: node index 3 doesn't exist",
+		},
+		{
+			name:       "Completed operation on removed node",
+			operations: []OperationRequest{{Id: "recreate", Type: OperationRecreateNode, NodeIndexes: []int{3}}},
+			status: QdrantClusterStatus{AvailableNodeIndexes: []int{0, 1, 2},
+				Operations: []OperationStatus{{Id: "recreate", Type: OperationRecreateNode, Phase: OperationSucceeded}}},
+		},
+		{
+			name: "Unknown node index after completed operation",
+			operations: []OperationRequest{
+				{Id: "restart", Type: OperationRestart},
+				{Id: "recreate", Type: OperationRecreateNode, NodeIndexes: []int{3}},
+			},
+			status: QdrantClusterStatus{AvailableNodeIndexes: []int{0, 1, 2},
+				Operations: []OperationStatus{{Id: "restart", Type: OperationRestart, Phase: OperationSucceeded}}},
+			expectedError: ".spec.operations[1]: node index 3 doesn't exist",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			qc := &QdrantCluster{Spec: QdrantClusterSpec{Operations: tt.operations}, Status: tt.status}
+			err := validateOperations(qc.Spec.Operations)
+			if err == nil {
+				err = qc.ValidateOperations()
+			}
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestOperationStatus(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	restart := OperationRequest{Id: "restart-1", Type: OperationRestart}
+	qc := &QdrantCluster{Spec: QdrantClusterSpec{Operations: []OperationRequest{restart}}}
+	assert.Len(t, qc.GetPendingOperations(), 1)
+
+	qc.SetOperationStatus(restart, OperationPending, "", now)
+	assert.Nil(t, qc.Status.GetOperationStatus("restart-1").StartTime)
+	qc.SetOperationStatus(restart, OperationRunning, "restarting node 0", now.Add(time.Minute))
+	qc.SetOperationStatus(restart, OperationSucceeded, "", now.Add(time.Hour))
+	require.Len(t, qc.Status.Operations, 1)
+	assert.Equal(t, OperationStatus{
+		Id:             "restart-1",
+		Type:           OperationRestart,
+		Phase:          OperationSucceeded,
+		StartTime:      &metav1.Time{Time: now.Add(time.Minute)},
+		CompletionTime: &metav1.Time{Time: now.Add(time.Hour)},
+	}, qc.Status.Operations[0])
+	assert.Empty(t, qc.GetPendingOperations(), "completed operations are not executed again")
+	assert.Nil(t, qc.Status.GetOperationStatus("unknown"))
+
+	// The statuses of operations which are no longer requested are dropped
+	reinit := OperationRequest{Id: "reinit-1", Type: OperationReinit}
+	qc.Spec.Operations = []OperationRequest{reinit}
+	qc.SetOperationStatus(reinit, OperationRunning, "", now.Add(2*time.Hour))
+	require.Len(t, qc.Status.Operations, 1)
+	assert.Equal(t, "reinit-1", qc.Status.Operations[0].Id)
+}
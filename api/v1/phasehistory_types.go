@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaxPhaseHistory is the maximum number of entries kept in QdrantClusterStatus.PhaseHistory
+const MaxPhaseHistory = 20
+
+// PhaseTransition specifies a transition of the cluster into a phase
+type PhaseTransition struct {
+	// Phase specifies the phase the cluster transitioned into
+	Phase ClusterPhase `json:"phase"`
+	// Reason specifies the reason for the phase
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Time specifies when the cluster transitioned into the phase
+	Time metav1.Time `json:"time"`
+	// ObservedGeneration specifies the most recent 'Generation' of the spec observed while the cluster was in the phase
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// SetPhase sets the phase and reason of the cluster and records the transition in the PhaseHistory.
+// A transition is only recorded if the phase differs from the last recorded transition,
+// otherwise the reason and observed generation of the last transition are updated (keeping its time),
+// so frequently changing reasons (e.g. progress messages) don't evict the phase transitions.
+// The oldest transitions are dropped if the history exceeds MaxPhaseHistory entries.
+// Returns true if a transition is recorded.
+func (s *QdrantClusterStatus) SetPhase(phase ClusterPhase, reason string, generation int64, now time.Time) bool {
+	s.Phase = phase
+	s.Reason = reason
+	if n := len(s.PhaseHistory); n > 0 {
+		last := &s.PhaseHistory[n-1]
+		if last.Phase == phase {
+			last.Reason = reason
+			last.ObservedGeneration = generation
+			return false
+		}
+	}
+	s.PhaseHistory = append(s.PhaseHistory, PhaseTransition{
+		Phase:              phase,
+		Reason:             reason,
+		Time:               metav1.NewTime(now),
+		ObservedGeneration: generation,
+	})
+	if excess := len(s.PhaseHistory) - MaxPhaseHistory; excess > 0 {
+		s.PhaseHistory = append(s.PhaseHistory[:0], s.PhaseHistory[excess:]...)
+	}
+	return true
+}
+
+// TimeInPhases returns the time the cluster spent in phases matching the given predicate in the period [since, now].
+// Time before the oldest recorded transition is unknown and not counted.
+func (s *QdrantClusterStatus) TimeInPhases(since, now time.Time, match func(ClusterPhase) bool) time.Duration {
+	if s == nil {
+		return 0
+	}
+	var result time.Duration
+	for i, transition := range s.PhaseHistory {
+		if !match(transition.Phase) {
+			continue
+		}
+		start := transition.Time.Time
+		end := now
+		if i+1 < len(s.PhaseHistory) {
+			end = s.PhaseHistory[i+1].Time.Time
+		}
+		if start.Before(since) {
+			start = since
+		}
+		if end.After(now) {
+			end = now
+		}
+		if end.After(start) {
+			result += end.Sub(start)
+		}
+	}
+	return result
+}
+
+// TimeInFailedPhases returns the time the cluster spent in failed phases (see ClusterPhase.IsFailed) in the period [since, now].
+func (s *QdrantClusterStatus) TimeInFailedPhases(since, now time.Time) time.Duration {
+	return s.TimeInPhases(since, now, ClusterPhase.IsFailed)
+}
+
+// CountTransitions returns the number of recorded transitions into the given phase since the given time.
+func (s *QdrantClusterStatus) CountTransitions(phase ClusterPhase, since time.Time) int {
+	if s == nil {
+		return 0
+	}
+	result := 0
+	for _, transition := range s.PhaseHistory {
+		if transition.Phase == phase && !transition.Time.Time.Before(since) {
+			result++
+		}
+	}
+	return result
+}
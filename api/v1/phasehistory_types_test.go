@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetPhase(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var status QdrantClusterStatus
+
+	assert.True(t, status.SetPhase(ClusterUpdating, "", 1, now))
+	assert.False(t, status.SetPhase(ClusterUpdating, "", 1, now.Add(time.Minute)), "duplicate transition")
+	// Reasons changing within a phase (e.g. progress messages) don't evict the transitions
+	for i := 0; i < 2*MaxPhaseHistory; i++ {
+		assert.False(t, status.SetPhase(ClusterUpdating, fmt.Sprintf("Waiting for node-%d", i), 1, now.Add(2*time.Minute)))
+	}
+	assert.Equal(t, ClusterUpdating, status.Phase)
+	assert.Equal(t, "Waiting for node-39", status.Reason)
+	require.Len(t, status.PhaseHistory, 1)
+	assert.Equal(t, "Waiting for node-39", status.PhaseHistory[0].Reason)
+	assert.Equal(t, now, status.PhaseHistory[0].Time.Time, "the time of the transition is kept")
+	// A new generation within the same phase is observed as well
+	assert.False(t, status.SetPhase(ClusterUpdating, "Waiting for node-0", 2, now.Add(150*time.Second)))
+	assert.Equal(t, int64(2), status.PhaseHistory[0].ObservedGeneration)
+	assert.Equal(t, now, status.PhaseHistory[0].Time.Time)
+	assert.True(t, status.SetPhase(ClusterHealthy, "", 1, now.Add(3*time.Minute)))
+	require.Len(t, status.PhaseHistory, 2)
+
+	for i := 0; i < 2*MaxPhaseHistory; i++ {
+		phase := ClusterUpdating
+		if i%2 == 0 {
+			phase = ClusterFailedToUpdate
+		}
+		status.SetPhase(phase, "", int64(i), now.Add(time.Duration(i)*time.Hour))
+	}
+	require.Len(t, status.PhaseHistory, MaxPhaseHistory)
+	assert.Equal(t, int64(MaxPhaseHistory), status.PhaseHistory[0].ObservedGeneration, "oldest entries are dropped")
+	assert.Equal(t, int64(2*MaxPhaseHistory-1), status.PhaseHistory[MaxPhaseHistory-1].ObservedGeneration)
+}
+
+func TestTimeInFailedPhases(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	var status QdrantClusterStatus
+	// Flapping overnight
+	status.SetPhase(ClusterFailedToUpdate, "", 1, now.Add(-30*time.Hour))
+	status.SetPhase(ClusterUpdating, "", 1, now.Add(-23*time.Hour))
+	status.SetPhase(ClusterFailedToUpdate, "", 1, now.Add(-22*time.Hour))
+	status.SetPhase(ClusterUpdating, "", 2, now.Add(-20*time.Hour))
+	status.SetPhase(ClusterHealthy, "", 2, now.Add(-19*time.Hour))
+	status.SetPhase(ClusterFailedToUpdate, "", 3, now.Add(-30*time.Minute))
+
+	// 1h (clipped) + 2h + 30m
+	assert.Equal(t, 3*time.Hour+30*time.Minute, status.TimeInFailedPhases(now.Add(-24*time.Hour), now))
+	assert.Equal(t, 30*time.Minute, status.TimeInFailedPhases(now.Add(-time.Hour), now))
+	assert.Equal(t, 2*time.Hour, status.TimeInPhases(now.Add(-24*time.Hour), now, func(p ClusterPhase) bool { return p == ClusterUpdating }))
+	assert.Equal(t, 2, status.CountTransitions(ClusterFailedToUpdate, now.Add(-24*time.Hour)))
+
+	var nilStatus *QdrantClusterStatus
+	assert.Zero(t, nilStatus.TimeInFailedPhases(now.Add(-24*time.Hour), now))
+}
@@ -0,0 +1,187 @@
+package v1
+
+import (
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// clusterPhaseClass classifies a ClusterPhase
+type clusterPhaseClass struct {
+	// transitioning is set if the operator is actively working on the cluster in this phase
+	transitioning bool
+	// failed is set if the operator failed to bring the cluster in the requested state
+	failed bool
+	// terminal is set if the operator won't leave this phase on its own, without a change of the spec (or the cluster)
+	terminal bool
+}
+
+// clusterPhaseClasses classifies all known cluster phases.
+// Every ClusterPhase constant must be listed here, which is enforced by a test.
+var clusterPhaseClasses = map[ClusterPhase]clusterPhaseClass{
+	ClusterCreating:          {transitioning: true},
+	ClusterFailedToCreate:    {failed: true, terminal: true},
+	ClusterUpdating:          {transitioning: true},
+	ClusterFailedToUpdate:    {failed: true, terminal: true},
+	ClusterScaling:           {transitioning: true},
+	ClusterUpgrading:         {transitioning: true},
+	ClusterSuspending:        {transitioning: true},
+	ClusterSuspended:         {terminal: true},
+	ClusterFailedToSuspend:   {failed: true, terminal: true},
+	ClusterResuming:          {transitioning: true},
+	ClusterFailedToResume:    {failed: true, terminal: true},
+	ClusterHealthy:           {terminal: true},
+	ClusterNotReady:          {},
+	ClusterRecoveryMode:      {},
+	ClusterManualMaintenance: {terminal: true},
+}
+
+// class returns the classification of the phase.
+// Unknown phases are classified by convention (see ClusterActiveStateSuffix and ClusterFailedStatePrefix).
+func (p ClusterPhase) class() clusterPhaseClass {
+	if c, found := clusterPhaseClasses[p]; found {
+		return c
+	}
+	failed := strings.HasPrefix(string(p), ClusterFailedStatePrefix)
+	return clusterPhaseClass{
+		transitioning: !failed && strings.HasSuffix(string(p), ClusterActiveStateSuffix),
+		failed:        failed,
+		terminal:      failed,
+	}
+}
+
+// IsTransitioning returns true if the operator is actively working on the cluster, e.g. Creating or Scaling.
+func (p ClusterPhase) IsTransitioning() bool {
+	return p.class().transitioning
+}
+
+// IsFailed returns true if the operator failed to bring the cluster in the requested state, e.g. FailedToCreate.
+func (p ClusterPhase) IsFailed() bool {
+	return p.class().failed
+}
+
+// IsTerminal returns true if the operator won't leave the phase on its own, e.g. Healthy, Suspended or FailedToUpdate.
+func (p ClusterPhase) IsTerminal() bool {
+	return p.class().terminal
+}
+
+// IsHealthy returns true if the cluster is healthy.
+func (p ClusterPhase) IsHealthy() bool {
+	return p == ClusterHealthy
+}
+
+// GetCondition returns the condition of the given type, nil if not found.
+func (s *QdrantClusterStatus) GetCondition(conditionType ClusterCondition) *metav1.Condition {
+	if s == nil {
+		return nil
+	}
+	return meta.FindStatusCondition(s.Conditions, string(conditionType))
+}
+
+// SetCondition sets the condition of the given type, the LastTransitionTime is only updated if the status changes.
+// Returns true if the conditions are changed.
+func (s *QdrantClusterStatus) SetCondition(conditionType ClusterCondition, status metav1.ConditionStatus, reason, message string, observedGeneration int64) bool {
+	return meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:               string(conditionType),
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: observedGeneration,
+	})
+}
+
+// SetAcceptingConnection sets the AcceptingConnection condition.
+// Returns true if the conditions are changed.
+func (s *QdrantClusterStatus) SetAcceptingConnection(accepting bool, reason, message string, observedGeneration int64) bool {
+	return s.SetCondition(ClusterConditionAcceptingConnection, conditionStatus(accepting), reason, message, observedGeneration)
+}
+
+// IsAcceptingConnection returns true if the AcceptingConnection condition is set to True.
+func (s *QdrantClusterStatus) IsAcceptingConnection() bool {
+	return s != nil && meta.IsStatusConditionTrue(s.Conditions, string(ClusterConditionAcceptingConnection))
+}
+
+// SetRecoveryMode sets the RecoveryMode condition.
+// Returns true if the conditions are changed.
+func (s *QdrantClusterStatus) SetRecoveryMode(enabled bool, reason, message string, observedGeneration int64) bool {
+	return s.SetCondition(ClusterConditionRecoveryMode, conditionStatus(enabled), reason, message, observedGeneration)
+}
+
+// IsInRecoveryMode returns true if the RecoveryMode condition is set to True.
+func (s *QdrantClusterStatus) IsInRecoveryMode() bool {
+	return s != nil && meta.IsStatusConditionTrue(s.Conditions, string(ClusterConditionRecoveryMode))
+}
+
+// conditionStatus converts a bool to a condition status
+func conditionStatus(b bool) metav1.ConditionStatus {
+	if b {
+		return metav1.ConditionTrue
+	}
+	return metav1.ConditionFalse
+}
+
+// GetDatabaseDiskUsagePercent returns the used space of the database volume in percent of its capacity.
+// The returned bool is false if the usage or capacity is unknown.
+func (n NodeStatus) GetDatabaseDiskUsagePercent() (int, bool) {
+	if n.DatabaseDiskUsage == nil {
+		return 0, false
+	}
+	capacity, found := n.DatabasePVCStatus.Capacity[corev1.ResourceStorage]
+	if !found || capacity.IsZero() {
+		return 0, false
+	}
+	return int(n.DatabaseDiskUsage.AsApproximateFloat64() * 100 / capacity.AsApproximateFloat64()), true
+}
+
+// GetLeaders returns the names of the nodes which consider themselves the Raft leader, sorted by name.
+func (s *QdrantClusterStatus) GetLeaders() []string {
+	if s == nil {
+		return nil
+	}
+	var result []string
+	for name, node := range s.Nodes {
+		if node.RaftRole == RaftRoleLeader {
+			result = append(result, name)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// HasConsensusSplit returns true if the nodes don't agree on the Raft leader,
+// meaning multiple nodes consider themselves the leader (in any term, e.g. a stale leader cut off by a partition),
+// or nodes in the same term see a different leader.
+// Nodes which don't report a leader (e.g. during an election) are ignored,
+// as are followers in an older term than the newest reported term (e.g. a follower lagging behind after an election).
+func (s *QdrantClusterStatus) HasConsensusSplit() bool {
+	if s == nil {
+		return false
+	}
+	if len(s.GetLeaders()) > 1 {
+		return true
+	}
+	var newestTerm int64
+	for _, node := range s.Nodes {
+		newestTerm = max(newestTerm, node.RaftTerm)
+	}
+	leaders := make(map[int64]string)
+	for _, node := range s.Nodes {
+		leader := node.LeaderPeerId
+		if node.RaftRole == RaftRoleLeader && node.PeerId != "" {
+			leader = node.PeerId
+		} else if node.RaftTerm < newestTerm {
+			continue
+		}
+		if leader == "" {
+			continue
+		}
+		if known, found := leaders[node.RaftTerm]; found && known != leader {
+			return true
+		}
+		leaders[node.RaftTerm] = leader
+	}
+	return false
+}
@@ -0,0 +1,232 @@
+package v1
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// declaredClusterPhases returns the values of all ClusterPhase constants declared in this package,
+// so the classification test doesn't depend on a hand maintained list.
+func declaredClusterPhases(t *testing.T) []ClusterPhase {
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+	var result []ClusterPhase
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		require.NoError(t, err)
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				if ident, ok := vs.Type.(*ast.Ident); ok && ident.Name == "ClusterPhase" {
+					for _, value := range vs.Values {
+						lit, ok := value.(*ast.BasicLit)
+						require.True(t, ok, "ClusterPhase constants are expected to be string literals")
+						phase, err := strconv.Unquote(lit.Value)
+						require.NoError(t, err)
+						result = append(result, ClusterPhase(phase))
+					}
+				}
+			}
+		}
+	}
+	return result
+}
+
+// TestClusterPhasesAreClassified fails if a ClusterPhase is added without adding it to clusterPhaseClasses.
+func TestClusterPhasesAreClassified(t *testing.T) {
+	declared := declaredClusterPhases(t)
+	require.NotEmpty(t, declared)
+	for _, phase := range declared {
+		_, found := clusterPhaseClasses[phase]
+		assert.True(t, found, "ClusterPhase %q needs to be added to clusterPhaseClasses", phase)
+	}
+	assert.Len(t, clusterPhaseClasses, len(declared), "clusterPhaseClasses contains phases which aren't declared")
+}
+
+// TestClusterPhaseClassesFollowConvention ensures the classification is consistent with the naming
+// convention, which is used by consumers parsing the phase themselves.
+func TestClusterPhaseClassesFollowConvention(t *testing.T) {
+	for phase, class := range clusterPhaseClasses {
+		assert.Equal(t, strings.HasSuffix(string(phase), ClusterActiveStateSuffix), class.transitioning, "phase %s", phase)
+		assert.Equal(t, strings.HasPrefix(string(phase), ClusterFailedStatePrefix), class.failed, "phase %s", phase)
+		if class.failed {
+			assert.True(t, class.terminal, "failed phase %s should be terminal", phase)
+		}
+		if class.transitioning {
+			assert.False(t, class.terminal, "transitioning phase %s can't be terminal", phase)
+		}
+	}
+}
+
+func TestClusterPhaseHelpers(t *testing.T) {
+	testCases := []struct {
+		phase         ClusterPhase
+		transitioning bool
+		failed        bool
+		terminal      bool
+		healthy       bool
+	}{
+		{phase: ClusterCreating, transitioning: true},
+		{phase: ClusterFailedToUpdate, failed: true, terminal: true},
+		{phase: ClusterSuspended, terminal: true},
+		{phase: ClusterHealthy, terminal: true, healthy: true},
+		{phase: ClusterRecoveryMode},
+		{phase: ""},
+		// Unknown phases are classified by convention
+		{phase: "Migrating", transitioning: true},
+		{phase: "FailedToMigrate", failed: true, terminal: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(string(tt.phase), func(t *testing.T) {
+			assert.Equal(t, tt.transitioning, tt.phase.IsTransitioning())
+			assert.Equal(t, tt.failed, tt.phase.IsFailed())
+			assert.Equal(t, tt.terminal, tt.phase.IsTerminal())
+			assert.Equal(t, tt.healthy, tt.phase.IsHealthy())
+		})
+	}
+}
+
+func TestClusterConditionHelpers(t *testing.T) {
+	var status QdrantClusterStatus
+	assert.False(t, status.IsAcceptingConnection())
+	assert.False(t, status.IsInRecoveryMode())
+	assert.Nil(t, status.GetCondition(ClusterConditionAcceptingConnection))
+
+	assert.True(t, status.SetAcceptingConnection(true, "NodesReady", "All nodes are ready", 3))
+	assert.True(t, status.IsAcceptingConnection())
+	cond := status.GetCondition(ClusterConditionAcceptingConnection)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, "NodesReady", cond.Reason)
+	assert.Equal(t, int64(3), cond.ObservedGeneration)
+	assert.False(t, cond.LastTransitionTime.IsZero())
+
+	assert.False(t, status.SetAcceptingConnection(true, "NodesReady", "All nodes are ready", 3), "no change expected")
+
+	assert.True(t, status.SetRecoveryMode(true, "RecoveryModeEnabled", "", 3))
+	assert.True(t, status.IsInRecoveryMode())
+	assert.True(t, status.SetRecoveryMode(false, "RecoveryModeDisabled", "", 4))
+	assert.False(t, status.IsInRecoveryMode())
+	assert.Len(t, status.Conditions, 2)
+
+	var nilStatus *QdrantClusterStatus
+	assert.False(t, nilStatus.IsAcceptingConnection())
+	assert.Nil(t, nilStatus.GetCondition(ClusterConditionRecoveryMode))
+}
+
+func TestGetDatabaseDiskUsagePercent(t *testing.T) {
+	capacity := NodePVCStatus{Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")}}
+	testCases := []struct {
+		name          string
+		node          NodeStatus
+		expected      int
+		expectedFound bool
+	}{
+		{name: "No usage", node: NodeStatus{DatabasePVCStatus: capacity}},
+		{name: "No capacity", node: NodeStatus{DatabaseDiskUsage: ptr.To(resource.MustParse("1Gi"))}},
+		{name: "Usage", node: NodeStatus{DatabasePVCStatus: capacity, DatabaseDiskUsage: ptr.To(resource.MustParse("8Gi"))}, expected: 80, expectedFound: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			percent, found := tt.node.GetDatabaseDiskUsagePercent()
+			assert.Equal(t, tt.expectedFound, found)
+			assert.Equal(t, tt.expected, percent)
+		})
+	}
+}
+
+func TestConsensusHelpers(t *testing.T) {
+	node := func(peerId string, role RaftRole, term int64, leader string) NodeStatus {
+		return NodeStatus{PeerId: peerId, RaftRole: role, RaftTerm: term, LeaderPeerId: leader}
+	}
+	testCases := []struct {
+		name            string
+		nodes           map[string]NodeStatus
+		expectedLeaders []string
+		expectedSplit   bool
+	}{
+		{name: "No nodes"},
+		{
+			name: "Healthy consensus",
+			nodes: map[string]NodeStatus{
+				"node-0": node("1", RaftRoleLeader, 2, "1"),
+				"node-1": node("2", RaftRoleFollower, 2, "1"),
+				"node-2": node("3", RaftRoleFollower, 2, "1"),
+			},
+			expectedLeaders: []string{"node-0"},
+		},
+		{
+			name: "Election in progress",
+			nodes: map[string]NodeStatus{
+				"node-0": node("1", RaftRoleCandidate, 3, ""),
+				"node-1": node("2", RaftRoleFollower, 2, "1"),
+			},
+		},
+		{
+			name: "Multiple leaders in the same term",
+			nodes: map[string]NodeStatus{
+				"node-0": node("1", RaftRoleLeader, 2, "1"),
+				"node-1": node("2", RaftRoleLeader, 2, "2"),
+			},
+			expectedLeaders: []string{"node-0", "node-1"},
+			expectedSplit:   true,
+		},
+		{
+			name: "Multiple leaders in different terms",
+			nodes: map[string]NodeStatus{
+				"node-0": node("1", RaftRoleLeader, 2, "1"),
+				"node-1": node("2", RaftRoleLeader, 3, "2"),
+				"node-2": node("3", RaftRoleFollower, 3, "2"),
+			},
+			expectedLeaders: []string{"node-0", "node-1"},
+			expectedSplit:   true,
+		},
+		{
+			// The follower didn't learn about the election in term 3 yet, which is not a split
+			name: "Follower lagging behind in an older term",
+			nodes: map[string]NodeStatus{
+				"node-0": node("1", RaftRoleLeader, 3, "1"),
+				"node-1": node("2", RaftRoleFollower, 2, "3"),
+			},
+			expectedLeaders: []string{"node-0"},
+		},
+		{
+			name: "Followers see different leaders in the same term",
+			nodes: map[string]NodeStatus{
+				"node-0": node("1", RaftRoleFollower, 3, "2"),
+				"node-1": node("2", RaftRoleFollower, 3, "3"),
+			},
+			expectedSplit: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			status := &QdrantClusterStatus{Nodes: tt.nodes}
+			assert.Equal(t, tt.expectedLeaders, status.GetLeaders())
+			assert.Equal(t, tt.expectedSplit, status.HasConsensusSplit())
+		})
+	}
+}
@@ -2,11 +2,15 @@ package v1
 
 import (
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/qdrant/kubernetes-api/api/kstatus"
 )
 
 //goland:noinspection GoUnusedConst
@@ -22,6 +26,7 @@ const (
 	// The value should be a [RFC3339 formatted] date.
 	// If the value is updated it will retrigger the restart.
 	// For historical reasons the key doesn't start with `operator.qdrant.com/`
+	// Use the typed helpers (e.g. SetRestartedAt) or QdrantClusterSpec.Operations instead of setting the operation annotations directly.
 	RestartedAtAnnotationKey = "restartedAt"
 	// OnDemandReplicationRestartAnnotationKey is the annotation key to trigger an on-demand replication restart.
 	// The annotation should be placed on the QdrantCluster instance.
@@ -82,7 +87,9 @@ type QdrantClusterSpec struct {
 	Id string `json:"id"`
 	// Version specifies the version of Qdrant to deploy
 	Version string `json:"version"`
-	// Size specifies the desired number of Qdrant nodes in the cluster
+	// Size specifies the desired number of Qdrant nodes in the cluster, including the nodes of the NodePools.
+	// The nodes which are not part of a NodePool form the default pool (see GetDefaultNodePoolSize),
+	// so changing Size (e.g. through the scale subresource) scales the default pool.
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=100
 	Size int `json:"size"`
@@ -102,7 +109,8 @@ type QdrantClusterSpec struct {
 	// +optional
 	Suspend bool `json:"suspend,omitempty"`
 	// Pauses specifies a list of pause request by developer for manual maintenance.
-	// Operator will skip handling any changes in the CR if any pause request is present.
+	// Operator will skip handling any changes in the CR if any active (not expired) pause request is present.
+	// Each owner can have at most one pause request.
 	// +optional
 	Pauses []Pause `json:"pauses,omitempty"`
 	// Image specifies the image to use for each Qdrant node.
@@ -160,6 +168,12 @@ type QdrantClusterSpec struct {
 	// +kubebuilder:default=Off
 	// +optional
 	OnDemandReplication OnDemandReplicationType `json:"onDemandReplication,omitempty"`
+	// UpdateStrategy specifies how the nodes are rolled when multiple nodes need to be restarted,
+	// like when the Qdrant version needs to be upgraded.
+	// This setting is ignored if RestartAllPodsConcurrently is enabled.
+	// If not set, the nodes are restarted one by one.
+	// +optional
+	UpdateStrategy *UpdateStrategy `json:"updateStrategy,omitempty"`
 	// If StartupDelaySeconds is set (> 0), an additional 'sleep <value>' will be emitted to the pod startup.
 	// The sleep will be added when a pod is restarted, it will not force any pod to restart.
 	// This feature can be used for debugging the core, e.g. if a pod is in crash loop, it provided a way
@@ -185,6 +199,53 @@ type QdrantClusterSpec struct {
 	// +kubebuilder:default=false
 	// +optional
 	MultiAZ bool `json:"multiAZ,omitempty"`
+	// Autoscaling specifies the autoscaling policy for the number of nodes and the resources of each node.
+	// If set, the operator manages Size and Resources within the configured bounds,
+	// so Size should not be changed by external autoscalers (through the scale subresource) anymore.
+	// +optional
+	Autoscaling *Autoscaling `json:"autoscaling,omitempty"`
+	// StorageAutoscaling specifies the policy to automatically expand the database volume of each node when it fills up.
+	// This requires a storage class which allows volume expansion.
+	// +optional
+	StorageAutoscaling *StorageAutoscaling `json:"storageAutoscaling,omitempty"`
+	// MaintenanceWindows specifies the recurring time windows in which disruptive operations
+	// (like version upgrades, restarts, on-demand replication restarts and rebalances) are allowed to start.
+	// Disruptive operations requested outside a maintenance window are deferred until the next window.
+	// If not set, disruptive operations can start at any time.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	// EmergencyMaintenance specifies whether to start disruptive operations immediately,
+	// ignoring the configured MaintenanceWindows.
+	// +kubebuilder:default=false
+	// +optional
+	EmergencyMaintenance bool `json:"emergencyMaintenance,omitempty"`
+	// Operations specifies operations (like restarts) to execute on the cluster.
+	// This is the declarative equivalent of the operation annotations (e.g. RestartedAtAnnotationKey).
+	// Every operation is executed once, the progress is reported in Status.Operations.
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	Operations []OperationRequest `json:"operations,omitempty"`
+	// DecommissionNodeIndexes specifies the indexes of the nodes to decommission.
+	// The shards of these nodes are drained (moved to other nodes) before the nodes are removed.
+	// If Size isn't lowered accordingly, the nodes are replaced by new nodes (with new indexes).
+	// The progress is reported in Status.Decommissions.
+	// +optional
+	DecommissionNodeIndexes []int `json:"decommissionNodeIndexes,omitempty"`
+	// NodePools specifies additional pools of nodes with their own resources, scheduling and storage classes,
+	// e.g. a few large-memory nodes or GPU indexing nodes.
+	// The nodes of the pools are part of Size.
+	// +kubebuilder:validation:MaxItems=10
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	NodePools []NodePool `json:"nodePools,omitempty"`
+	// NodeOverrides specifies settings of individual nodes (by node index) which override
+	// the settings of the cluster and its NodePools, e.g. to give a single node more memory or pin it to a zone.
+	// See GetNodeSpec for the effective settings of a node.
+	// +listType=map
+	// +listMapKey=nodeIndex
+	// +optional
+	NodeOverrides []NodeOverride `json:"nodeOverrides,omitempty"`
 }
 
 // Validate if there are incorrect settings in the CRD
@@ -196,9 +257,62 @@ func (s QdrantClusterSpec) Validate() error {
 	if err := s.Storage.Validate(); err != nil {
 		return err
 	}
+	if err := validatePauses(s.Pauses); err != nil {
+		return err
+	}
+	if err := s.validateAutoscaling(); err != nil {
+		return err
+	}
+	if err := s.StorageAutoscaling.Validate(s.Resources.Storage); err != nil {
+		return err
+	}
+	if err := s.UpdateStrategy.Validate(); err != nil {
+		return err
+	}
+	if s.UpdateStrategy != nil && s.RestartAllPodsConcurrently != nil && *s.RestartAllPodsConcurrently {
+		return fmt.Errorf(".spec.updateStrategy: can not be combined with restartAllPodsConcurrently")
+	}
+	for i, w := range s.MaintenanceWindows {
+		if err := w.Validate(fmt.Sprintf(".spec.maintenanceWindows[%d]", i)); err != nil {
+			return err
+		}
+	}
+	if err := validateOperations(s.Operations); err != nil {
+		return err
+	}
+	if err := s.validateDecommission(); err != nil {
+		return err
+	}
+	if err := s.validateNodePools(); err != nil {
+		return err
+	}
+	if err := s.validateNodeOverrides(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// IsPaused returns true if at least one of the pause requests is active at the given time.
+func (s QdrantClusterSpec) IsPaused(now time.Time) bool {
+	for _, p := range s.Pauses {
+		if p.IsActive(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// ActivePauses returns the pause requests which are active (not expired) at the given time.
+func (s QdrantClusterSpec) ActivePauses(now time.Time) []Pause {
+	var result []Pause
+	for _, p := range s.Pauses {
+		if p.IsActive(now) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
 // GetServicePerNode get the service per node, taking the default (true) into concideration
 func (s QdrantClusterSpec) GetServicePerNode() bool {
 	if s.ServicePerNode == nil {
@@ -207,6 +321,103 @@ func (s QdrantClusterSpec) GetServicePerNode() bool {
 	return *s.ServicePerNode
 }
 
+// UpdateStrategy specifies how the nodes are rolled during an update.
+type UpdateStrategy struct {
+	// MaxUnavailable specifies the maximum number of nodes which can be unavailable during the update.
+	// The value can be an absolute number (e.g. 2) or a percentage of Spec.Size (e.g. 25%).
+	// A percentage is rounded down, with a minimum of 1 node. The value must be at least 1 (or 1%).
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// Partition specifies the node index from which the nodes are updated (like the partition of a StatefulSet).
+	// Only nodes with an index greater than or equal to Partition are updated, other nodes keep their current version.
+	// This allows staged rollouts by lowering the partition step by step.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Partition *int `json:"partition,omitempty"`
+	// Canary specifies to update a single (canary) node first and wait until it is healthy
+	// for the configured soak time before the other nodes are updated.
+	// +optional
+	Canary *CanaryUpdate `json:"canary,omitempty"`
+}
+
+// CanaryUpdate specifies the canary phase of an update.
+type CanaryUpdate struct {
+	// NodeIndex specifies the index of the node to use as canary.
+	// If not set, the node with the highest index (which is to be updated) will be used.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	NodeIndex *int `json:"nodeIndex,omitempty"`
+	// SoakTime specifies how long the canary node needs to be healthy, before the update continues.
+	// +kubebuilder:default="10m"
+	// +optional
+	SoakTime *metav1.Duration `json:"soakTime,omitempty"`
+}
+
+// Validate if there are incorrect settings in the update strategy
+func (u *UpdateStrategy) Validate() error {
+	if u == nil {
+		return nil
+	}
+	if u.MaxUnavailable != nil {
+		// Scaling to 100 nodes keeps absolute values as is and turns percentages into their number
+		value, err := intstr.GetScaledValueFromIntOrPercent(u.MaxUnavailable, 100, false)
+		if err != nil {
+			return fmt.Errorf(".spec.updateStrategy.maxUnavailable error: %w", err)
+		}
+		if value < 1 {
+			return fmt.Errorf(".spec.updateStrategy.maxUnavailable: must be at least 1 (or 1%%)")
+		}
+	}
+	if u.Canary != nil && u.Canary.SoakTime != nil && u.Canary.SoakTime.Duration < 0 {
+		return fmt.Errorf(".spec.updateStrategy.canary.soakTime: must not be negative")
+	}
+	return nil
+}
+
+// GetMaxUnavailable returns the maximum number of unavailable nodes for a cluster of the given size,
+// taking the default (1) into consideration. The result is always at least 1.
+func (u *UpdateStrategy) GetMaxUnavailable(size int) int {
+	if u == nil || u.MaxUnavailable == nil {
+		return 1
+	}
+	result, err := intstr.GetScaledValueFromIntOrPercent(u.MaxUnavailable, size, false)
+	if err != nil || result < 1 {
+		return 1
+	}
+	return result
+}
+
+// GetPartition returns the partition of the update, taking the default (0, meaning all nodes) into consideration.
+func (u *UpdateStrategy) GetPartition() int {
+	if u == nil || u.Partition == nil {
+		return 0
+	}
+	return *u.Partition
+}
+
+// ShouldUpdateNode returns true if the node with the given index is part of the update (see Partition).
+func (u *UpdateStrategy) ShouldUpdateNode(nodeIndex int) bool {
+	return nodeIndex >= u.GetPartition()
+}
+
+// GetCanary returns the canary configuration, nil if no canary should be used.
+func (u *UpdateStrategy) GetCanary() *CanaryUpdate {
+	if u == nil {
+		return nil
+	}
+	return u.Canary
+}
+
+// GetSoakTime returns the soak time of the canary node, taking the default (10m) into consideration.
+func (c *CanaryUpdate) GetSoakTime() time.Duration {
+	if c == nil || c.SoakTime == nil {
+		return 10 * time.Minute
+	}
+	return c.SoakTime.Duration
+}
+
 type ReadCluster struct {
 	// Id specifies the unique identifier of the read cluster
 	Id string `json:"id"`
@@ -348,7 +559,52 @@ type Pause struct {
 	// Reason specifies the reason for the pause request.
 	Reason string `json:"reason,omitempty"`
 	// CreationTimestamp specifies the time when the pause request was created.
+	// This is a free-form string for backwards compatibility, use CreatedAt instead.
+	// +optional
 	CreationTimestamp string `json:"creationTimestamp,omitempty"`
+	// CreatedAt specifies the time when the pause request was created.
+	// +optional
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+	// ExpiresAt specifies the time when the pause request expires.
+	// An expired pause request is ignored by the operator, so the cluster is managed again
+	// without the need to remove the pause request.
+	// If not set, the pause request never expires.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// GetCreationTime returns the time the pause request was created,
+// which is CreatedAt if set, CreationTimestamp if it's a RFC3339 timestamp otherwise.
+// Returns false if the creation time is unknown.
+func (p Pause) GetCreationTime() (time.Time, bool) {
+	if p.CreatedAt != nil {
+		return p.CreatedAt.Time, true
+	}
+	t, err := time.Parse(time.RFC3339, p.CreationTimestamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// IsActive returns true if the pause request is not expired at the given time.
+func (p Pause) IsActive(now time.Time) bool {
+	return p.ExpiresAt == nil || now.Before(p.ExpiresAt.Time)
+}
+
+// validatePauses validates the list of pause requests
+func validatePauses(pauses []Pause) error {
+	owners := make(map[string]bool, len(pauses))
+	for i, p := range pauses {
+		if owners[p.Owner] {
+			return fmt.Errorf(".spec.pauses[%d]: duplicate pause request for owner %q", i, p.Owner)
+		}
+		owners[p.Owner] = true
+		if created, found := p.GetCreationTime(); found && p.ExpiresAt != nil && !p.ExpiresAt.After(created) {
+			return fmt.Errorf(".spec.pauses[%d]: expiresAt must be after the creation time", i)
+		}
+	}
+	return nil
 }
 
 type QdrantImage struct {
@@ -576,6 +832,13 @@ type StoragePerformanceConfig struct {
 	AsyncScorer *bool `json:"async_scorer,omitempty"`
 }
 
+func (c *QdrantConfiguration) GetCollection() *QdrantConfigurationCollection {
+	if c == nil {
+		return nil
+	}
+	return c.Collection
+}
+
 func (c *QdrantConfiguration) GetService() *QdrantConfigurationService {
 	if c == nil {
 		return nil
@@ -605,6 +868,14 @@ type QdrantConfigurationCollection struct {
 	StrictMode *QdrantConfigurationCollectionStrictMode `json:"strict_mode,omitempty"`
 }
 
+// GetReplicationFactor returns the default replication factor, taking the Qdrant default (1) into consideration
+func (c *QdrantConfigurationCollection) GetReplicationFactor() int64 {
+	if c == nil || c.ReplicationFactor == nil {
+		return 1
+	}
+	return *c.ReplicationFactor
+}
+
 type QdrantConfigurationCollectionStrictMode struct {
 	// MaxPayloadIndexCount represents the maximal number of payload indexes allowed to be created.
 	// It can be set for Qdrant version >= 1.16.0
@@ -982,6 +1253,9 @@ type ClusterCondition string
 const (
 	ClusterConditionAcceptingConnection ClusterCondition = "AcceptingConnection"
 	ClusterConditionRecoveryMode        ClusterCondition = "RecoveryMode"
+	ClusterConditionReady               ClusterCondition = kstatus.ConditionReady
+	ClusterConditionReconciling         ClusterCondition = kstatus.ConditionReconciling
+	ClusterConditionStalled             ClusterCondition = kstatus.ConditionStalled
 )
 
 // QdrantClusterStatus defines the observed state of QdrantCluster
@@ -993,6 +1267,13 @@ type QdrantClusterStatus struct {
 	// Reason specifies the reason for the phase of the cluster
 	// +optional
 	Reason string `json:"reason,omitempty"`
+	// ObservedGeneration is the 'Generation' of the resource that was last processed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// PhaseHistory specifies the last phase transitions of the cluster, oldest first (see SetPhase).
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	PhaseHistory []PhaseTransition `json:"phaseHistory,omitempty"`
 	// AvailableNodes specifies the number of available nodes in the cluster
 	// +optional
 	AvailableNodes int `json:"availableNodes,omitempty"`
@@ -1021,7 +1302,12 @@ type QdrantClusterStatus struct {
 	// The node index used in a scale down (see ScaleDownAllowed)
 	// If this field is not set the last index in AvailableNodeIndexes will be used.
 	ScaleDownNodeIndex *int `json:"ScaleDownNodeIndex,omitempty"`
-	// Conditions specifies the conditions of different checks on the cluster
+	// LastAllocatedNodeIndex specifies the highest node index ever allocated for the cluster (see AllocateNodeIndex).
+	// Indexes up to this value are never re-used, even if they aren't part of AvailableNodeIndexes anymore.
+	// +optional
+	LastAllocatedNodeIndex *int `json:"lastAllocatedNodeIndex,omitempty"`
+	// Conditions specifies the conditions of different checks on the cluster,
+	// including the kstatus compliant Ready, Reconciling and Stalled conditions
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 	// Nodes specifies the status of the nodes in the cluster
@@ -1043,6 +1329,72 @@ type QdrantClusterStatus struct {
 	// Selector is the label query to find the pods (used as status for PodDisruptionBudget)
 	// +optional
 	Selector *string `json:"selector,omitempty"`
+	// Collections specifies the status of the data in the cluster, as reported by Qdrant.
+	// +optional
+	Collections *CollectionsStatus `json:"collections,omitempty"`
+	// Autoscaling specifies the last decision of the autoscaler, if any.
+	// +optional
+	Autoscaling *AutoscalingStatus `json:"autoscaling,omitempty"`
+	// Update specifies the progress of the current (rolling) update, if any.
+	// +optional
+	Update *UpdateStatus `json:"update,omitempty"`
+	// DeferredOperations specifies the disruptive operations which are requested,
+	// but deferred until the next maintenance window (see Spec.MaintenanceWindows).
+	// +optional
+	DeferredOperations []DeferredOperation `json:"deferredOperations,omitempty"`
+	// Operations specifies the status of the operations requested in Spec.Operations.
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	Operations []OperationStatus `json:"operations,omitempty"`
+	// Decommissions specifies the progress of the nodes being decommissioned (see Spec.DecommissionNodeIndexes).
+	// +optional
+	Decommissions []NodeDecommissionStatus `json:"decommissions,omitempty"`
+	// NodePools specifies the status of the node pools, including the default pool.
+	// +optional
+	NodePools []NodePoolStatus `json:"nodePools,omitempty"`
+}
+
+// UpdatePhase specifies the phase of a (rolling) update
+type UpdatePhase string
+
+//goland:noinspection GoUnusedConst
+const (
+	UpdatePhaseCanary      UpdatePhase = "Canary"
+	UpdatePhaseSoaking     UpdatePhase = "Soaking"
+	UpdatePhaseRolling     UpdatePhase = "Rolling"
+	UpdatePhasePartitioned UpdatePhase = "Partitioned"
+	UpdatePhaseCompleted   UpdatePhase = "Completed"
+)
+
+type UpdateStatus struct {
+	// Phase specifies the phase of the update
+	// +kubebuilder:validation:Enum=Canary;Soaking;Rolling;Partitioned;Completed
+	// +optional
+	Phase UpdatePhase `json:"phase,omitempty"`
+	// TargetVersion specifies the version the nodes are updated to
+	// +optional
+	TargetVersion string `json:"targetVersion,omitempty"`
+	// CanaryNodeIndex specifies the index of the canary node, if a canary is used
+	// +optional
+	CanaryNodeIndex *int `json:"canaryNodeIndex,omitempty"`
+	// CanaryHealthySince specifies the time since when the canary node is healthy.
+	// The update continues if the canary node is healthy for at least the configured soak time.
+	// +optional
+	CanaryHealthySince *metav1.Time `json:"canaryHealthySince,omitempty"`
+	// UpdatedNodes specifies the number of nodes running the target version
+	// +optional
+	UpdatedNodes int `json:"updatedNodes,omitempty"`
+	// StartedAt specifies the time when the update started
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+}
+
+// CanarySoaked returns true if the canary node is healthy for at least the given soak time.
+func (u *UpdateStatus) CanarySoaked(now time.Time, soakTime time.Duration) bool {
+	if u == nil || u.CanaryHealthySince == nil {
+		return false
+	}
+	return !now.Before(u.CanaryHealthySince.Add(soakTime))
 }
 
 type ClusterManagerReponse struct {
@@ -1085,6 +1437,10 @@ type NodeStatus struct {
 	// Version specifies the version of Qdrant running on the node
 	// +optional
 	Version string `json:"version,omitempty"`
+	// TargetVersion specifies the version of Qdrant the node should run.
+	// It differs from Version while the node is (waiting to be) updated.
+	// +optional
+	TargetVersion string `json:"targetVersion,omitempty"`
 	// Reports if qdrant node responded to liveness request (before readiness).
 	// This is needed to beter report recovery process to the user.
 	// +optional
@@ -1117,6 +1473,36 @@ type NodeStatus struct {
 	// +optional
 	RestartCount int32 `json:"restartCount,omitempty"`
 
+	// PeerId specifies the consensus peer id of the node
+	// (a string, because the id is an unsigned 64-bit number)
+	// +optional
+	PeerId string `json:"peerId,omitempty"`
+	// RaftRole specifies the role of the node in the Raft consensus
+	// +optional
+	RaftRole RaftRole `json:"raftRole,omitempty"`
+	// RaftTerm specifies the current Raft term as seen by the node
+	// +optional
+	RaftTerm int64 `json:"raftTerm,omitempty"`
+	// LeaderPeerId specifies the consensus peer id of the leader as seen by the node
+	// +optional
+	LeaderPeerId string `json:"leaderPeerId,omitempty"`
+	// CommitIndex specifies the last committed index of the Raft log on the node
+	// +optional
+	CommitIndex int64 `json:"commitIndex,omitempty"`
+	// LastSeen specifies the last time the node responded to the operator
+	// +optional
+	LastSeen *metav1.Time `json:"lastSeen,omitempty"`
+	// DatabaseDiskUsage specifies the used space of the database volume.
+	// The capacity of the volume is reported in DatabasePVCStatus.
+	// +optional
+	DatabaseDiskUsage *resource.Quantity `json:"databaseDiskUsage,omitempty"`
+	// MemoryWorkingSet specifies the memory working set of the main qdrant container
+	// +optional
+	MemoryWorkingSet *resource.Quantity `json:"memoryWorkingSet,omitempty"`
+
+	// NodePool specifies the name of the node pool the node belongs to
+	// +optional
+	NodePool string `json:"nodePool,omitempty"`
 	// Status of the database storage PVC
 	// +optional
 	DatabasePVCStatus NodePVCStatus `json:"databasePVCStatus,omitempty"`
@@ -1125,6 +1511,22 @@ type NodeStatus struct {
 	SnapshotsPVCStatus NodePVCStatus `json:"snapshotsPVCStatus,omitempty"`
 }
 
+// RaftRole specifies the role of a node in the Raft consensus
+type RaftRole string
+
+//goland:noinspection GoUnusedConst
+const (
+	RaftRoleLeader       RaftRole = "Leader"
+	RaftRoleFollower     RaftRole = "Follower"
+	RaftRoleCandidate    RaftRole = "Candidate"
+	RaftRolePreCandidate RaftRole = "PreCandidate"
+)
+
+// IsUpToDate returns true if the node runs its target version
+func (n NodeStatus) IsUpToDate() bool {
+	return n.TargetVersion == "" || n.Version == n.TargetVersion
+}
+
 type NodePVCStatus struct {
 	// Name of the StorageClass used by the PVC
 	// +optional
@@ -1170,6 +1572,23 @@ type QdrantCluster struct {
 	Status QdrantClusterStatus `json:"status,omitempty"`
 }
 
+// GetKStatus computes the kstatus of the cluster.
+// If the operator doesn't report the Ready condition (yet), the status is derived from the phase.
+func (qc *QdrantCluster) GetKStatus() kstatus.Result {
+	if r, ok := kstatus.FromConditions(qc.Generation, qc.Status.ObservedGeneration, qc.Status.Conditions); ok {
+		return r
+	}
+	phase := qc.Status.Phase
+	switch {
+	case phase.IsFailed():
+		return kstatus.Result{Status: kstatus.Failed, Message: qc.Status.Reason}
+	case phase == ClusterHealthy || phase == ClusterSuspended:
+		return kstatus.Result{Status: kstatus.Current, Message: qc.Status.Reason}
+	default:
+		return kstatus.Result{Status: kstatus.InProgress, Message: qc.Status.Reason}
+	}
+}
+
 //+kubebuilder:object:root=true
 
 // QdrantClusterList contains a list of QdrantCluster
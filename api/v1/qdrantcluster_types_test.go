@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 )
 
@@ -183,6 +186,91 @@ func TestValidate(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		{
+			name: "Pauses of different owners",
+			spec: QdrantClusterSpec{
+				Resources: Resources{
+					CPU:     "100m",
+					Memory:  "1Gi",
+					Storage: "2Gi",
+				},
+				Pauses: []Pause{
+					{Owner: "alice", Reason: "debugging"},
+					{Owner: "bob", Reason: "migration"},
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Duplicate pause of the same owner",
+			spec: QdrantClusterSpec{
+				Resources: Resources{
+					CPU:     "100m",
+					Memory:  "1Gi",
+					Storage: "2Gi",
+				},
+				Pauses: []Pause{
+					{Owner: "alice", Reason: "debugging"},
+					{Owner: "bob", Reason: "migration"},
+					{Owner: "alice", Reason: "still debugging"},
+				},
+			},
+			expectedError: fmt.Errorf(".spec.pauses[2]: duplicate pause request for owner \"alice\""),
+		},
+		{
+			name: "Pause expires before it is created",
+			spec: QdrantClusterSpec{
+				Resources: Resources{
+					CPU:     "100m",
+					Memory:  "1Gi",
+					Storage: "2Gi",
+				},
+				Pauses: []Pause{
+					{
+						Owner:     "alice",
+						CreatedAt: ptr.To(metav1.NewTime(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))),
+						ExpiresAt: ptr.To(metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))),
+					},
+				},
+			},
+			expectedError: fmt.Errorf(".spec.pauses[0]: expiresAt must be after the creation time"),
+		},
+		{
+			name: "Pause expires before its legacy creation timestamp",
+			spec: QdrantClusterSpec{
+				Resources: Resources{
+					CPU:     "100m",
+					Memory:  "1Gi",
+					Storage: "2Gi",
+				},
+				Pauses: []Pause{
+					{
+						Owner:             "alice",
+						CreationTimestamp: "2024-01-02T00:00:00Z",
+						ExpiresAt:         ptr.To(metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))),
+					},
+				},
+			},
+			expectedError: fmt.Errorf(".spec.pauses[0]: expiresAt must be after the creation time"),
+		},
+		{
+			name: "Pause with free-form creation timestamp",
+			spec: QdrantClusterSpec{
+				Resources: Resources{
+					CPU:     "100m",
+					Memory:  "1Gi",
+					Storage: "2Gi",
+				},
+				Pauses: []Pause{
+					{
+						Owner:             "alice",
+						CreationTimestamp: "yesterday",
+						ExpiresAt:         ptr.To(metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))),
+					},
+				},
+			},
+			expectedError: nil,
+		},
 	}
 
 	for _, tt := range testCases {
@@ -196,3 +284,159 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestIsPaused(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	permanent := Pause{Owner: "alice", Reason: "debugging"}
+	expired := Pause{Owner: "bob", ExpiresAt: ptr.To(metav1.NewTime(now.Add(-time.Hour)))}
+	active := Pause{Owner: "carol", ExpiresAt: ptr.To(metav1.NewTime(now.Add(time.Hour)))}
+
+	testCases := []struct {
+		name           string
+		pauses         []Pause
+		expectedPaused bool
+		expectedActive []Pause
+	}{
+		{
+			name:           "No pauses",
+			expectedPaused: false,
+		},
+		{
+			name:           "Pause without expiry",
+			pauses:         []Pause{permanent},
+			expectedPaused: true,
+			expectedActive: []Pause{permanent},
+		},
+		{
+			name:           "Only expired pauses",
+			pauses:         []Pause{expired},
+			expectedPaused: false,
+		},
+		{
+			name:           "Expired and active pauses",
+			pauses:         []Pause{expired, active, permanent},
+			expectedPaused: true,
+			expectedActive: []Pause{active, permanent},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := QdrantClusterSpec{Pauses: tt.pauses}
+			assert.Equal(t, tt.expectedPaused, spec.IsPaused(now))
+			assert.Equal(t, tt.expectedActive, spec.ActivePauses(now))
+		})
+	}
+}
+
+func TestPauseIsActiveAtExpiry(t *testing.T) {
+	expiresAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	p := Pause{Owner: "alice", ExpiresAt: ptr.To(metav1.NewTime(expiresAt))}
+
+	assert.True(t, p.IsActive(expiresAt.Add(-time.Second)))
+	assert.False(t, p.IsActive(expiresAt), "a pause is expired at its expiry time")
+}
+
+func TestPauseLegacyCreationTimestamp(t *testing.T) {
+	var qc QdrantCluster
+	data := `{"spec":{"id":"abc","pauses":[{"owner":"alice","creationTimestamp":"yesterday"},{"owner":"bob","creationTimestamp":"2024-01-01T00:00:00Z"}]}}`
+	if assert.NoError(t, json.Unmarshal([]byte(data), &qc)) && assert.Len(t, qc.Spec.Pauses, 2) {
+		assert.Equal(t, "yesterday", qc.Spec.Pauses[0].CreationTimestamp)
+		_, found := qc.Spec.Pauses[0].GetCreationTime()
+		assert.False(t, found)
+		created, found := qc.Spec.Pauses[1].GetCreationTime()
+		assert.True(t, found)
+		assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), created)
+	}
+}
+
+func TestUpdateStrategyGetMaxUnavailable(t *testing.T) {
+	testCases := []struct {
+		name     string
+		strategy *UpdateStrategy
+		size     int
+		expected int
+	}{
+		{name: "No strategy", strategy: nil, size: 5, expected: 1},
+		{name: "No max unavailable", strategy: &UpdateStrategy{}, size: 5, expected: 1},
+		{name: "Absolute value", strategy: &UpdateStrategy{MaxUnavailable: ptr.To(intstr.FromInt32(2))}, size: 5, expected: 2},
+		{name: "Percentage rounded down", strategy: &UpdateStrategy{MaxUnavailable: ptr.To(intstr.FromString("50%"))}, size: 5, expected: 2},
+		{name: "Percentage of at least 1 node", strategy: &UpdateStrategy{MaxUnavailable: ptr.To(intstr.FromString("10%"))}, size: 3, expected: 1},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.strategy.GetMaxUnavailable(tt.size))
+		})
+	}
+}
+
+func TestUpdateStrategyValidate(t *testing.T) {
+	resources := Resources{CPU: "100m", Memory: "1Gi", Storage: "2Gi"}
+	testCases := []struct {
+		name          string
+		spec          QdrantClusterSpec
+		expectedError string
+	}{
+		{
+			name: "Canary with partition",
+			spec: QdrantClusterSpec{Resources: resources, UpdateStrategy: &UpdateStrategy{
+				Partition: ptr.To(2),
+				Canary:    &CanaryUpdate{SoakTime: &metav1.Duration{Duration: time.Minute}},
+			}},
+		},
+		{
+			name:          "Zero max unavailable",
+			spec:          QdrantClusterSpec{Resources: resources, UpdateStrategy: &UpdateStrategy{MaxUnavailable: ptr.To(intstr.FromInt32(0))}},
+			expectedError: ".spec.updateStrategy.maxUnavailable: must be at least 1 (or 1%)",
+		},
+		{
+			name:          "Zero percent max unavailable",
+			spec:          QdrantClusterSpec{Resources: resources, UpdateStrategy: &UpdateStrategy{MaxUnavailable: ptr.To(intstr.FromString("0%"))}},
+			expectedError: ".spec.updateStrategy.maxUnavailable: must be at least 1 (or 1%)",
+		},
+		{
+			name:          "Invalid max unavailable percentage",
+			spec:          QdrantClusterSpec{Resources: resources, UpdateStrategy: &UpdateStrategy{MaxUnavailable: ptr.To(intstr.FromString("half"))}},
+			expectedError: `.spec.updateStrategy.maxUnavailable error: invalid value for IntOrString: invalid type: string is not a percentage`,
+		},
+		{
+			name: "Combined with restartAllPodsConcurrently",
+			spec: QdrantClusterSpec{
+				Resources:                  resources,
+				RestartAllPodsConcurrently: ptr.To(true),
+				UpdateStrategy:             &UpdateStrategy{Canary: &CanaryUpdate{}},
+			},
+			expectedError: ".spec.updateStrategy: can not be combined with restartAllPodsConcurrently",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate()
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestUpdateStrategyPartitionAndCanary(t *testing.T) {
+	var strategy *UpdateStrategy
+	assert.True(t, strategy.ShouldUpdateNode(0))
+	assert.Nil(t, strategy.GetCanary())
+	assert.Equal(t, 10*time.Minute, strategy.GetCanary().GetSoakTime())
+
+	strategy = &UpdateStrategy{Partition: ptr.To(3)}
+	assert.False(t, strategy.ShouldUpdateNode(2))
+	assert.True(t, strategy.ShouldUpdateNode(3))
+	assert.True(t, strategy.ShouldUpdateNode(7))
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	status := &UpdateStatus{CanaryHealthySince: ptr.To(metav1.NewTime(now.Add(-5 * time.Minute)))}
+	assert.False(t, status.CanarySoaked(now, 10*time.Minute))
+	assert.True(t, status.CanarySoaked(now, 5*time.Minute))
+	assert.False(t, (*UpdateStatus)(nil).CanarySoaked(now, 0))
+}
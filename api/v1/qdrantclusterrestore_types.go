@@ -1,6 +1,14 @@
 package v1
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/qdrant/kubernetes-api/api/kstatus"
+)
 
 //goland:noinspection GoUnusedConst
 const (
@@ -16,11 +24,97 @@ type QdrantClusterRestoreSpec struct {
 	Destination RestoreDestination `json:"destination"`
 }
 
+// RestoreSource specifies the snapshot to restore from.
+// Exactly one of SnapshotName, ClusterId or Selector has to be set.
+// When ClusterId or Selector is set, the most recent successful snapshot matching it
+// (and taken before Before, if set) is restored.
 type RestoreSource struct {
 	// SnapshotName is the name of the snapshot from which we wish to restore
-	SnapshotName string `json:"snapshotName"`
+	// +optional
+	SnapshotName string `json:"snapshotName,omitempty"`
 	// Namespace of the snapshot
 	Namespace string `json:"namespace"`
+	// ClusterId selects the snapshots of the cluster with the given ID
+	// +optional
+	ClusterId string `json:"clusterId,omitempty"`
+	// Selector selects the snapshots matching the label selector
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Before restricts the selected snapshots to the ones taken before the given time
+	// +optional
+	Before *metav1.Time `json:"before,omitempty"`
+}
+
+// Validate validates the source specifies exactly one way to select the snapshot.
+func (s RestoreSource) Validate() error {
+	selectors := 0
+	for _, set := range []bool{s.SnapshotName != "", s.ClusterId != "", s.Selector != nil} {
+		if set {
+			selectors++
+		}
+	}
+	if selectors != 1 {
+		return errors.New(".spec.source: exactly one of snapshotName, clusterId or selector must be set")
+	}
+	if s.Before != nil && s.SnapshotName != "" {
+		return errors.New(".spec.source.before: cannot be used with snapshotName")
+	}
+	if s.Selector != nil {
+		// An empty selector matches every snapshot in the namespace, which is never the intent
+		if len(s.Selector.MatchLabels) == 0 && len(s.Selector.MatchExpressions) == 0 {
+			return errors.New(".spec.source.selector: at least one of matchLabels or matchExpressions must be set")
+		}
+		if _, err := metav1.LabelSelectorAsSelector(s.Selector); err != nil {
+			return fmt.Errorf(".spec.source.selector error: %w", err)
+		}
+	}
+	return nil
+}
+
+// ResolveSnapshot returns the snapshot of the list the source refers to.
+// For SnapshotName this is the snapshot with that name, which doesn't need to be successful (yet).
+// Otherwise, this is the most recent successful snapshot matching the ClusterId or Selector, taken before Before if set.
+func (s RestoreSource) ResolveSnapshot(list *QdrantClusterSnapshotList) (*QdrantClusterSnapshot, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	var selector labels.Selector
+	if s.Selector != nil {
+		// Error is already checked by Validate
+		selector, _ = metav1.LabelSelectorAsSelector(s.Selector)
+	}
+	var result *QdrantClusterSnapshot
+	for i := range list.Items {
+		qcs := &list.Items[i]
+		if qcs.Namespace != s.Namespace {
+			continue
+		}
+		if s.SnapshotName != "" {
+			if qcs.Name == s.SnapshotName {
+				return qcs, nil
+			}
+			continue
+		}
+		if qcs.Status.Phase != SnapshotSucceeded {
+			continue
+		}
+		if s.ClusterId != "" && qcs.Spec.ClusterId != s.ClusterId {
+			continue
+		}
+		if selector != nil && !selector.Matches(labels.Set(qcs.Labels)) {
+			continue
+		}
+		if s.Before != nil && !qcs.GetSnapshotTime().Before(s.Before.Time) {
+			continue
+		}
+		if result == nil || qcs.GetSnapshotTime().After(result.GetSnapshotTime()) {
+			result = qcs
+		}
+	}
+	if result == nil {
+		return nil, fmt.Errorf("no snapshot found in namespace %q matching the source", s.Namespace)
+	}
+	return result, nil
 }
 
 type RestoreDestination struct {
@@ -34,6 +128,23 @@ type RestoreDestination struct {
 	// to the specified state.
 	// +optional
 	Create bool `json:"create"`
+	// Template specifies settings of the created cluster which override the settings of the snapshotted cluster,
+	// e.g. to restore into a cluster with a different shape.
+	// Can only be used if Create is true.
+	// +optional
+	Template *RestoreClusterTemplate `json:"template,omitempty"`
+}
+
+// Validate validates the source of the restore, and the destination against the spec of the cluster
+// the snapshot is taken from and the snapshot to restore from (nil if not known or resolved yet).
+func (s QdrantClusterRestoreSpec) Validate(source *QdrantClusterSpec, snapshot *QdrantClusterSnapshot) error {
+	if err := s.Source.Validate(); err != nil {
+		return err
+	}
+	if s.Destination.Template != nil && !s.Destination.Create {
+		return errors.New(".spec.destination.template: can only be used if create is true")
+	}
+	return s.Destination.Template.Validate(source, snapshot)
 }
 
 type RestorePhase string
@@ -56,6 +167,29 @@ type QdrantClusterRestoreStatus struct {
 	// Message from the operator in case of failures, like snapshot not found
 	// +optional
 	Message *string `json:"message,omitempty"`
+	// ObservedGeneration is the 'Generation' of the resource that was last processed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions specifies the conditions of the resource, including the kstatus compliant Ready, Reconciling and Stalled conditions
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Snapshot specifies the snapshot the source is resolved to
+	// +optional
+	Snapshot *ResolvedSnapshot `json:"snapshot,omitempty"`
+}
+
+// ResolvedSnapshot specifies the snapshot a RestoreSource is resolved to
+type ResolvedSnapshot struct {
+	// Name of the snapshot
+	Name string `json:"name"`
+	// Namespace of the snapshot
+	Namespace string `json:"namespace"`
+	// ClusterId is the ID of the cluster the snapshot is taken from
+	// +optional
+	ClusterId string `json:"clusterId,omitempty"`
+	// SnapshotTime is the time the snapshot is taken
+	// +optional
+	SnapshotTime *metav1.Time `json:"snapshotTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -73,6 +207,50 @@ type QdrantClusterRestore struct {
 	Status QdrantClusterRestoreStatus `json:"status,omitempty"`
 }
 
+// GetKStatus computes the kstatus of the restore.
+// If the operator doesn't report the Ready condition (yet), the status is derived from the phase.
+func (qcr *QdrantClusterRestore) GetKStatus() kstatus.Result {
+	if r, ok := kstatus.FromConditions(qcr.Generation, qcr.Status.ObservedGeneration, qcr.Status.Conditions); ok {
+		return r
+	}
+	message := ""
+	if qcr.Status.Message != nil {
+		message = *qcr.Status.Message
+	}
+	switch qcr.Status.Phase {
+	case RestoreSucceeded, RestoreSkipped:
+		return kstatus.Result{Status: kstatus.Current, Message: message}
+	case RestoreFailed:
+		return kstatus.Result{Status: kstatus.Failed, Message: message}
+	default:
+		return kstatus.Result{Status: kstatus.InProgress, Message: message}
+	}
+}
+
+// ResolveSnapshot resolves the source of the restore to a snapshot of the list and records it in the status.
+// Once resolved, the recorded snapshot is returned, so a restore doesn't change its snapshot when newer snapshots are taken.
+func (qcr *QdrantClusterRestore) ResolveSnapshot(list *QdrantClusterSnapshotList) (*QdrantClusterSnapshot, error) {
+	if resolved := qcr.Status.Snapshot; resolved != nil {
+		for i := range list.Items {
+			if list.Items[i].Name == resolved.Name && list.Items[i].Namespace == resolved.Namespace {
+				return &list.Items[i], nil
+			}
+		}
+		return nil, fmt.Errorf("resolved snapshot %s/%s not found", resolved.Namespace, resolved.Name)
+	}
+	qcs, err := qcr.Spec.Source.ResolveSnapshot(list)
+	if err != nil {
+		return nil, err
+	}
+	qcr.Status.Snapshot = &ResolvedSnapshot{
+		Name:         qcs.Name,
+		Namespace:    qcs.Namespace,
+		ClusterId:    qcs.Spec.ClusterId,
+		SnapshotTime: &metav1.Time{Time: qcs.GetSnapshotTime()},
+	}
+	return qcs, nil
+}
+
 //+kubebuilder:object:root=true
 
 // QdrantClusterRestoreList contains a list of QdrantClusterRestore objects
@@ -0,0 +1,153 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func restoreSnapshot(name, clusterId string, phase QdrantClusterSnapshotPhase, taken time.Time, labels map[string]string) QdrantClusterSnapshot {
+	return QdrantClusterSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "qdrant", Labels: labels},
+		Spec:       QdrantClusterSnapshotSpec{ClusterId: clusterId, CreationTimestamp: taken.Unix()},
+		Status:     QdrantClusterSnapshotStatus{Phase: phase},
+	}
+}
+
+func TestRestoreSourceValidate(t *testing.T) {
+	before := metav1.NewTime(time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC))
+	testCases := []struct {
+		name          string
+		source        RestoreSource
+		expectedError string
+	}{
+		{name: "Snapshot name", source: RestoreSource{SnapshotName: "snap", Namespace: "qdrant"}},
+		{name: "Cluster ID before", source: RestoreSource{ClusterId: "abc", Before: &before}},
+		{name: "Selector", source: RestoreSource{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"release": "v42"}}}},
+		{
+			name:          "None",
+			source:        RestoreSource{Namespace: "qdrant"},
+			expectedError: ".spec.source: exactly one of snapshotName, clusterId or selector must be set",
+		},
+		{
+			name:          "Name and cluster ID",
+			source:        RestoreSource{SnapshotName: "snap", ClusterId: "abc"},
+			expectedError: ".spec.source: exactly one of snapshotName, clusterId or selector must be set",
+		},
+		{
+			name:          "Name before",
+			source:        RestoreSource{SnapshotName: "snap", Before: &before},
+			expectedError: ".spec.source.before: cannot be used with snapshotName",
+		},
+		{
+			name:          "Empty selector",
+			source:        RestoreSource{Selector: &metav1.LabelSelector{}},
+			expectedError: ".spec.source.selector: at least one of matchLabels or matchExpressions must be set",
+		},
+		{
+			name: "Invalid selector",
+			source: RestoreSource{Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "release", Operator: "Unknown"},
+			}}},
+			expectedError: `.spec.source.selector error: "Unknown" is not a valid label selector operator`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.source.Validate()
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestRestoreSourceResolveSnapshot(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	release := func(v string) map[string]string { return map[string]string{"release": v} }
+	list := &QdrantClusterSnapshotList{Items: []QdrantClusterSnapshot{
+		restoreSnapshot("a-12", "a", SnapshotSucceeded, base.Add(12*time.Hour), release("v41")),
+		restoreSnapshot("a-13", "a", SnapshotSucceeded, base.Add(13*time.Hour), release("v42")),
+		restoreSnapshot("a-14", "a", SnapshotSucceeded, base.Add(14*time.Hour), release("v42")),
+		restoreSnapshot("a-15", "a", SnapshotFailed, base.Add(15*time.Hour), release("v43")),
+		restoreSnapshot("b-16", "b", SnapshotSucceeded, base.Add(16*time.Hour), nil),
+	}}
+	before := func(h int) *metav1.Time { return &metav1.Time{Time: base.Add(time.Duration(h) * time.Hour)} }
+
+	testCases := []struct {
+		name          string
+		source        RestoreSource
+		expected      string
+		expectedError string
+	}{
+		{name: "Snapshot name", source: RestoreSource{SnapshotName: "a-15", Namespace: "qdrant"}, expected: "a-15"},
+		{name: "Latest of cluster", source: RestoreSource{ClusterId: "a", Namespace: "qdrant"}, expected: "a-14"},
+		{name: "Latest of cluster before", source: RestoreSource{ClusterId: "a", Namespace: "qdrant", Before: before(14)}, expected: "a-13"},
+		{
+			name:     "Label",
+			source:   RestoreSource{Selector: &metav1.LabelSelector{MatchLabels: release("v42")}, Namespace: "qdrant"},
+			expected: "a-14",
+		},
+		{
+			name:          "Failed only",
+			source:        RestoreSource{Selector: &metav1.LabelSelector{MatchLabels: release("v43")}, Namespace: "qdrant"},
+			expectedError: `no snapshot found in namespace "qdrant" matching the source`,
+		},
+		{
+			name:          "Other namespace",
+			source:        RestoreSource{ClusterId: "b", Namespace: "default"},
+			expectedError: `no snapshot found in namespace "default" matching the source`,
+		},
+		{
+			name:          "Too early",
+			source:        RestoreSource{ClusterId: "a", Namespace: "qdrant", Before: before(12)},
+			expectedError: `no snapshot found in namespace "qdrant" matching the source`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			qcs, err := tt.source.ResolveSnapshot(list)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, qcs.Name)
+		})
+	}
+}
+
+func TestRestoreResolveSnapshot(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	list := &QdrantClusterSnapshotList{Items: []QdrantClusterSnapshot{
+		restoreSnapshot("a-12", "a", SnapshotSucceeded, base.Add(12*time.Hour), nil),
+	}}
+	qcr := &QdrantClusterRestore{Spec: QdrantClusterRestoreSpec{Source: RestoreSource{ClusterId: "a", Namespace: "qdrant"}}}
+
+	qcs, err := qcr.ResolveSnapshot(list)
+	require.NoError(t, err)
+	assert.Equal(t, "a-12", qcs.Name)
+	assert.Equal(t, &ResolvedSnapshot{
+		Name:         "a-12",
+		Namespace:    "qdrant",
+		ClusterId:    "a",
+		SnapshotTime: &metav1.Time{Time: base.Add(12 * time.Hour)},
+	}, qcr.Status.Snapshot)
+
+	// A newer snapshot doesn't change the resolved one
+	list.Items = append(list.Items, restoreSnapshot("a-13", "a", SnapshotSucceeded, base.Add(13*time.Hour), nil))
+	qcs, err = qcr.ResolveSnapshot(list)
+	require.NoError(t, err)
+	assert.Equal(t, "a-12", qcs.Name)
+
+	list.Items = list.Items[1:]
+	_, err = qcr.ResolveSnapshot(list)
+	assert.EqualError(t, err, "resolved snapshot qdrant/a-12 not found")
+}
@@ -1,6 +1,15 @@
 package v1
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/qdrant/kubernetes-api/api/kstatus"
+)
 
 //goland:noinspection GoUnusedConst
 const (
@@ -16,12 +25,101 @@ type QdrantClusterScheduledSnapshotSpec struct {
 	// +kubebuilder:validation:MaxLength=8
 	ScheduleShortId string `json:"scheduleShortId"`
 	// Cron expression for frequency of creating snapshots, see https://en.wikipedia.org/wiki/Cron.
-	// The schedule is specified in UTC.
+	// Supported are the macros (e.g. @daily), @every <duration> and expressions with 5 to 7 fields (see ParseSchedule).
+	// The schedule is evaluated in the TimeZone, unless it starts with CRON_TZ=<time zone> or TZ=<time zone>.
 	// +kubebuilder:validation:Pattern=`^(@(annually|yearly|monthly|weekly|daily|hourly|reboot))|(@every (\d+(ns|us|µs|ms|s|m|h))+)|((((\d+,)+\d+|([\d\*]+(\/|-)\d+)|\d+|\*) ?){5,7})$`
 	Schedule string `json:"schedule"`
+	// TimeZone specifies the IANA time zone the schedule is evaluated in, e.g. "Europe/Berlin".
+	// A CRON_TZ= or TZ= prefix of the schedule takes precedence.
+	// +kubebuilder:default=UTC
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
 	// Retention of schedule in hours
+	// If RetentionPolicy is set as well, snapshots are kept while they are within the retention or kept by the policy.
+	// In that case the snapshots don't get a retention of their own, so the policy wins over the expiry of the snapshots
+	// (see GetSnapshotRetention).
 	// +kubebuilder:validation:Pattern=^[0-9]+h$
+	// +kubebuilder:validation:MaxLength=8
 	Retention string `json:"retention"`
+	// RetentionPolicy specifies which snapshots to keep, independent of their age.
+	// +optional
+	RetentionPolicy *SnapshotRetentionPolicy `json:"retentionPolicy,omitempty"`
+	// Method specifies how the snapshots are taken, see QdrantClusterSnapshotSpec.Method
+	// +kubebuilder:validation:Enum=VolumeSnapshot;QdrantAPI
+	// +optional
+	Method SnapshotMethod `json:"method,omitempty"`
+	// Destination specifies where the snapshot files are exported to, only supported by the QdrantAPI method.
+	// +optional
+	Destination *SnapshotDestination `json:"destination,omitempty"`
+	// Suspend specifies whether to suspend the creation of new snapshots, existing snapshots are not affected.
+	// +kubebuilder:default=false
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// ConcurrencyPolicy specifies how to treat a run while the snapshot of the previous run is still running:
+	// Allow starts the new snapshot anyway, Forbid (default) skips the new run, Replace deletes the running snapshot.
+	// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+	// +kubebuilder:default=Forbid
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	// StartingDeadlineSeconds specifies the deadline in seconds for starting a snapshot after its scheduled time,
+	// e.g. after an operator downtime. Runs which missed their deadline are skipped.
+	// If not set, missed runs are started (once) whenever possible.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+	// SuccessfulSnapshotsHistoryLimit specifies the maximum number of successful snapshots to keep,
+	// in addition to the Retention and RetentionPolicy. If not set, there is no limit.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SuccessfulSnapshotsHistoryLimit *int `json:"successfulSnapshotsHistoryLimit,omitempty"`
+	// FailedSnapshotsHistoryLimit specifies the maximum number of failed (or skipped) snapshots to keep,
+	// in addition to the Retention and RetentionPolicy. If not set, there is no limit.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailedSnapshotsHistoryLimit *int `json:"failedSnapshotsHistoryLimit,omitempty"`
+}
+
+// ConcurrencyPolicy specifies how to treat concurrent runs of a schedule
+type ConcurrencyPolicy string
+
+//goland:noinspection GoUnusedConst
+const (
+	AllowConcurrent   ConcurrencyPolicy = "Allow"
+	ForbidConcurrent  ConcurrencyPolicy = "Forbid"
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+// GetConcurrencyPolicy returns the concurrency policy, Forbid if not set.
+func (s QdrantClusterScheduledSnapshotSpec) GetConcurrencyPolicy() ConcurrencyPolicy {
+	if s.ConcurrencyPolicy == "" {
+		return ForbidConcurrent
+	}
+	return s.ConcurrencyPolicy
+}
+
+// GetMethod returns the snapshot method, VolumeSnapshot if not set.
+func (s QdrantClusterScheduledSnapshotSpec) GetMethod() SnapshotMethod {
+	if s.Method == "" {
+		return SnapshotMethodVolumeSnapshot
+	}
+	return s.Method
+}
+
+// Validate validates the schedule, the retention policy, and the snapshot method and destination against the capabilities of the region.
+func (s QdrantClusterScheduledSnapshotSpec) Validate(region QdrantCloudRegionStatus) error {
+	if _, err := ParseSchedule(s.Schedule); err != nil {
+		return fmt.Errorf(".spec.schedule error: %w", err)
+	}
+	if _, err := s.GetLocation(); err != nil {
+		return err
+	}
+	if _, err := ParseRetention(s.Retention); err != nil {
+		return fmt.Errorf(".spec.retention error: %w", err)
+	}
+	if err := s.RetentionPolicy.Validate(); err != nil {
+		return err
+	}
+	return validateSnapshotMethod(s.GetMethod(), nil, s.Destination, region)
 }
 
 type ScheduledSnapshotPhase string
@@ -42,6 +140,21 @@ type QdrantClusterScheduledSnapshotStatus struct {
 	// Message from the operator in case of failures, like schedule not valid
 	// +optional
 	Message *string `json:"message,omitempty"`
+	// LastScheduleTime specifies the scheduled time of the last run which was started
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastSuccessfulTime specifies when the last snapshot of the schedule succeeded
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+	// Active specifies the references to the snapshots of the schedule which are running
+	// +optional
+	Active []corev1.ObjectReference `json:"active,omitempty"`
+	// ObservedGeneration is the 'Generation' of the resource that was last processed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions specifies the conditions of the resource, including the kstatus compliant Ready, Reconciling and Stalled conditions
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -50,7 +163,9 @@ type QdrantClusterScheduledSnapshotStatus struct {
 // +kubebuilder:printcolumn:name="scheduleShortId",type=string,JSONPath=`.spec.scheduleShortId`
 // +kubebuilder:printcolumn:name="schedule",type=string,JSONPath=`.spec.schedule`
 // +kubebuilder:printcolumn:name="retention",type=string,JSONPath=`.spec.retention`
+// +kubebuilder:printcolumn:name="suspend",type=boolean,JSONPath=`.spec.suspend`
 // +kubebuilder:printcolumn:name="scheduled",type=string,JSONPath=`.status.scheduled`
+// +kubebuilder:printcolumn:name="lastSchedule",type=date,JSONPath=`.status.lastScheduleTime`
 // +kubebuilder:printcolumn:name="age",type=date,JSONPath=`.metadata.creationTimestamp`
 // +kubebuilder:subresource:status
 
@@ -63,6 +178,26 @@ type QdrantClusterScheduledSnapshot struct {
 	Status QdrantClusterScheduledSnapshotStatus `json:"status,omitempty"`
 }
 
+// GetKStatus computes the kstatus of the scheduled snapshot.
+// If the operator doesn't report the Ready condition (yet), the status is derived from the phase.
+// A disabled schedule with a message (e.g. the schedule isn't valid) is considered failed.
+func (qcss *QdrantClusterScheduledSnapshot) GetKStatus() kstatus.Result {
+	if r, ok := kstatus.FromConditions(qcss.Generation, qcss.Status.ObservedGeneration, qcss.Status.Conditions); ok {
+		return r
+	}
+	switch qcss.Status.Phase {
+	case ScheduleActive:
+		return kstatus.Result{Status: kstatus.Current}
+	case ScheduleDisabled:
+		if qcss.Status.Message != nil && *qcss.Status.Message != "" {
+			return kstatus.Result{Status: kstatus.Failed, Message: *qcss.Status.Message}
+		}
+		return kstatus.Result{Status: kstatus.Current}
+	default:
+		return kstatus.Result{Status: kstatus.InProgress}
+	}
+}
+
 //+kubebuilder:object:root=true
 
 // QdrantClusterScheduledSnapshotList contains a list of QdrantCluster
@@ -75,3 +210,109 @@ type QdrantClusterScheduledSnapshotList struct {
 func init() {
 	registerTypes(&QdrantClusterScheduledSnapshot{}, &QdrantClusterScheduledSnapshotList{})
 }
+
+// maxMissedRuns is the maximum number of missed runs GetDueRun iterates over before narrowing the window to search in
+const maxMissedRuns = 100
+
+// GetDueRun returns the scheduled time of the most recent run which is due at the given time, but not started yet.
+// Runs after LastScheduleTime (or the creation of the resource) are considered, only the most recent one is returned,
+// so a run missed during a downtime is started once. Returns false if no run is due, the schedule is suspended,
+// or the most recent run missed its StartingDeadlineSeconds.
+func (qcss *QdrantClusterScheduledSnapshot) GetDueRun(now time.Time) (time.Time, bool, error) {
+	if qcss.Spec.Suspend {
+		return time.Time{}, false, nil
+	}
+	since := qcss.CreationTimestamp.Time
+	if last := qcss.Status.LastScheduleTime; last != nil {
+		since = last.Time
+	}
+	if d := qcss.Spec.StartingDeadlineSeconds; d != nil {
+		if earliest := now.Add(-time.Duration(*d) * time.Second); since.Before(earliest) {
+			since = earliest
+		}
+	}
+	// Search backwards from now in growing windows, so the runs missed over a long time
+	// (e.g. an old schedule without LastScheduleTime) are not iterated one by one.
+	for window := time.Second; ; window *= 2 {
+		start, complete := now.Add(-window), false
+		if !start.After(since) {
+			start, complete = since, true
+		}
+		due, found, err := qcss.Spec.lastRun(start, now)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		if found || complete {
+			return due, found, nil
+		}
+	}
+}
+
+// lastRun returns the most recent run after start, which is not after end.
+// If there are more than maxMissedRuns runs in between (e.g. a burst of runs every second),
+// the window is narrowed by bisection, instead of iterating all runs.
+func (s QdrantClusterScheduledSnapshotSpec) lastRun(start, end time.Time) (time.Time, bool, error) {
+	for {
+		var due time.Time
+		found := false
+		for after, i := start, 0; i <= maxMissedRuns; i++ {
+			next, ok, err := s.NextRun(after)
+			if err != nil {
+				return time.Time{}, false, err
+			}
+			if !ok || next.After(end) {
+				return due, found, nil
+			}
+			due, found, after = next, true, next
+		}
+		// Continue in the later half if it contains a run, the most recent run is in the earlier half otherwise
+		mid := start.Add(end.Sub(start) / 2)
+		next, ok, err := s.NextRun(mid)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		if ok && !next.After(end) {
+			start = mid
+		} else {
+			end = mid
+		}
+	}
+}
+
+// RecordRun records the start of a snapshot for the run at the given scheduled time.
+// Recording the same snapshot again (e.g. on a retried reconcile) doesn't add it to the active snapshots twice.
+func (s *QdrantClusterScheduledSnapshotStatus) RecordRun(snapshot *QdrantClusterSnapshot, scheduled time.Time) {
+	s.LastScheduleTime = &metav1.Time{Time: scheduled}
+	if slices.ContainsFunc(s.Active, func(ref corev1.ObjectReference) bool {
+		return ref.UID == snapshot.UID && ref.Name == snapshot.Name && ref.Namespace == snapshot.Namespace
+	}) {
+		return
+	}
+	s.Active = append(s.Active, corev1.ObjectReference{
+		APIVersion: GroupVersion.String(),
+		Kind:       KindQdrantClusterSnapshot,
+		Namespace:  snapshot.Namespace,
+		Name:       snapshot.Name,
+		UID:        snapshot.UID,
+	})
+}
+
+// RecordCompletion removes the completed snapshot from the active snapshots,
+// and records when the snapshot completed as LastSuccessfulTime if the snapshot succeeded:
+// its snapshot time plus its CompletionTime, or the given time if the CompletionTime is unknown.
+// LastSuccessfulTime never moves backwards, e.g. when an older snapshot completes after a newer one.
+func (s *QdrantClusterScheduledSnapshotStatus) RecordCompletion(snapshot *QdrantClusterSnapshot, now time.Time) {
+	s.Active = slices.DeleteFunc(s.Active, func(ref corev1.ObjectReference) bool {
+		return ref.Name == snapshot.Name && ref.Namespace == snapshot.Namespace
+	})
+	if snapshot.Status.Phase != SnapshotSucceeded {
+		return
+	}
+	completed := now
+	if d := snapshot.Status.CompletionTime; d != nil {
+		completed = snapshot.GetSnapshotTime().Add(d.Duration)
+	}
+	if s.LastSuccessfulTime == nil || completed.After(s.LastSuccessfulTime.Time) {
+		s.LastSuccessfulTime = &metav1.Time{Time: completed}
+	}
+}
@@ -0,0 +1,150 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestGetDueRun(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC)
+	hour := func(h int) time.Time { return time.Date(2024, 1, 1, h, 0, 0, 0, time.UTC) }
+	testCases := []struct {
+		name          string
+		spec          QdrantClusterScheduledSnapshotSpec
+		created       *time.Time
+		lastSchedule  *time.Time
+		now           time.Time
+		expected      time.Time
+		expectedFound bool
+		expectedError string
+	}{
+		{name: "Not due yet", spec: QdrantClusterScheduledSnapshotSpec{Schedule: "@hourly"}, now: created.Add(10 * time.Minute)},
+		{name: "Due", spec: QdrantClusterScheduledSnapshotSpec{Schedule: "@hourly"}, now: hour(1).Add(time.Second), expected: hour(1), expectedFound: true},
+		{name: "Already started", spec: QdrantClusterScheduledSnapshotSpec{Schedule: "@hourly"}, lastSchedule: ptr.To(hour(1)), now: hour(1).Add(time.Minute)},
+		{name: "Most recent missed run", spec: QdrantClusterScheduledSnapshotSpec{Schedule: "@hourly"}, lastSchedule: ptr.To(hour(1)), now: hour(5).Add(time.Minute), expected: hour(5), expectedFound: true},
+		{name: "Suspended", spec: QdrantClusterScheduledSnapshotSpec{Schedule: "@hourly", Suspend: true}, now: hour(5)},
+		{
+			name:          "Within starting deadline",
+			spec:          QdrantClusterScheduledSnapshotSpec{Schedule: "@hourly", StartingDeadlineSeconds: ptr.To(int64(300))},
+			lastSchedule:  ptr.To(hour(1)),
+			now:           hour(5).Add(4 * time.Minute),
+			expected:      hour(5),
+			expectedFound: true,
+		},
+		{
+			name:         "Missed starting deadline",
+			spec:         QdrantClusterScheduledSnapshotSpec{Schedule: "@hourly", StartingDeadlineSeconds: ptr.To(int64(300))},
+			lastSchedule: ptr.To(hour(1)),
+			now:          hour(5).Add(6 * time.Minute),
+		},
+		{
+			name:          "Created long ago",
+			spec:          QdrantClusterScheduledSnapshotSpec{Schedule: "@hourly"},
+			created:       ptr.To(created.AddDate(-1, 0, 0)),
+			now:           hour(5).Add(time.Minute),
+			expected:      hour(5),
+			expectedFound: true,
+		},
+		{
+			name:          "Monthly created long ago",
+			spec:          QdrantClusterScheduledSnapshotSpec{Schedule: "@monthly"},
+			created:       ptr.To(created.AddDate(-3, 0, 0)),
+			now:           hour(5),
+			expected:      hour(0),
+			expectedFound: true,
+		},
+		{
+			name:          "Burst of missed runs",
+			spec:          QdrantClusterScheduledSnapshotSpec{Schedule: "* * 0 1 1 *"},
+			created:       ptr.To(created.AddDate(-1, 0, 0)),
+			now:           hour(6),
+			expected:      hour(0).Add(59*time.Minute + 59*time.Second),
+			expectedFound: true,
+		},
+		{
+			name:          "Invalid schedule",
+			spec:          QdrantClusterScheduledSnapshotSpec{Schedule: "@sometimes"},
+			now:           hour(6),
+			expectedError: ".spec.schedule error: invalid schedule \"@sometimes\": expected 5 to 7 fields, got 1",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			qcss := &QdrantClusterScheduledSnapshot{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}, Spec: tt.spec}
+			if tt.created != nil {
+				qcss.CreationTimestamp = metav1.NewTime(*tt.created)
+			}
+			if tt.lastSchedule != nil {
+				qcss.Status.LastScheduleTime = &metav1.Time{Time: *tt.lastSchedule}
+			}
+			due, found, err := qcss.GetDueRun(tt.now)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedFound, found)
+			assert.True(t, tt.expected.Equal(due), "expected %s, got %s", tt.expected, due)
+		})
+	}
+}
+
+func TestRecordRun(t *testing.T) {
+	now := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+	snapshot := &QdrantClusterSnapshot{ObjectMeta: metav1.ObjectMeta{Name: "snapshot-1", Namespace: "qdrant", UID: "uid-1"}}
+	var status QdrantClusterScheduledSnapshotStatus
+
+	status.RecordRun(snapshot, now)
+	status.RecordRun(snapshot, now)
+	assert.Equal(t, now, status.LastScheduleTime.Time)
+	require.Len(t, status.Active, 1, "recording the same snapshot twice is idempotent")
+	assert.Equal(t, KindQdrantClusterSnapshot, status.Active[0].Kind)
+	assert.Equal(t, "snapshot-1", status.Active[0].Name)
+
+	snapshot.Status.Phase = SnapshotFailed
+	status.RecordCompletion(snapshot, now.Add(time.Minute))
+	assert.Empty(t, status.Active)
+	assert.Nil(t, status.LastSuccessfulTime)
+
+	// Without a CompletionTime, the time of recording is used
+	status.RecordRun(snapshot, now)
+	snapshot.Status.Phase = SnapshotSucceeded
+	status.RecordCompletion(snapshot, now.Add(time.Minute))
+	assert.Equal(t, now.Add(time.Minute), status.LastSuccessfulTime.Time)
+	assert.Equal(t, ForbidConcurrent, QdrantClusterScheduledSnapshotSpec{}.GetConcurrencyPolicy())
+
+	// The completion time is taken from the snapshot, even if it is recorded late
+	snapshot.Spec.CreationTimestamp = now.Add(time.Hour).Unix()
+	snapshot.Status.CompletionTime = &metav1.Duration{Duration: 3 * time.Minute}
+	status.RecordCompletion(snapshot, now.Add(5*time.Hour))
+	assert.Equal(t, now.Add(time.Hour+3*time.Minute), status.LastSuccessfulTime.Time)
+
+	// An older snapshot completing later doesn't move it backwards
+	older := snapshot.DeepCopy()
+	older.Spec.CreationTimestamp = now.Unix()
+	status.RecordCompletion(older, now.Add(5*time.Hour))
+	assert.Equal(t, now.Add(time.Hour+3*time.Minute), status.LastSuccessfulTime.Time)
+}
+
+func TestSnapshotHistoryLimits(t *testing.T) {
+	now := time.Date(2024, 6, 15, 3, 0, 0, 0, time.UTC)
+	list := dailySnapshots(now, 6, SnapshotSucceeded)
+	list.Items[1].Status.Phase = SnapshotFailed
+	list.Items[2].Status.Phase = SnapshotFailed
+	list.Items[3].Status.Phase = SnapshotSkipped
+
+	qcss := &QdrantClusterScheduledSnapshot{Spec: QdrantClusterScheduledSnapshotSpec{
+		ClusterId: "cluster-1", ScheduleShortId: "daily", Schedule: "@daily", Retention: "240h",
+		SuccessfulSnapshotsHistoryLimit: ptr.To(2),
+		FailedSnapshotsHistoryLimit:     ptr.To(1),
+	}}
+	pruned, err := qcss.GetSnapshotsToPrune(list, now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"snapshot-2", "snapshot-3", "snapshot-5"}, snapshotNames(pruned))
+}
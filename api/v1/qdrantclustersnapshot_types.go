@@ -1,8 +1,17 @@
 package v1
 
 import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
 	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/qdrant/kubernetes-api/api/kstatus"
 )
 
 //goland:noinspection GoUnusedConst
@@ -26,8 +35,96 @@ type QdrantClusterSnapshotSpec struct {
 	// The retention period of this snapshot in hours, if any.
 	// If not set, the backup doesn't have a retention period, meaning it will not be removed.
 	// +kubebuilder:validation:Pattern=^[0-9]+h$
+	// +kubebuilder:validation:MaxLength=8
 	// +optional
 	Retention *string `json:"retention,omitempty"`
+	// Method specifies how the snapshot is taken.
+	// VolumeSnapshot (default) takes CSI VolumeSnapshots of the database volumes, which requires a snapshot capable CSI driver.
+	// QdrantAPI takes snapshots with the Qdrant snapshot API, which works in every region.
+	// +kubebuilder:validation:Enum=VolumeSnapshot;QdrantAPI
+	// +optional
+	Method SnapshotMethod `json:"method,omitempty"`
+	// Collections specifies the collections to snapshot, only supported by the QdrantAPI method.
+	// If not set, a full snapshot of the cluster is taken.
+	// +optional
+	Collections []string `json:"collections,omitempty"`
+	// Destination specifies where the snapshot files are exported to, only supported by the QdrantAPI method.
+	// If not set, the snapshot files are kept in the cluster.
+	// +optional
+	Destination *SnapshotDestination `json:"destination,omitempty"`
+	// Consistency specifies the consistency of a VolumeSnapshot.
+	// CrashConsistent (default) takes the VolumeSnapshots of the live nodes,
+	// ApplicationConsistent flushes and locks writes through the Qdrant API before taking the VolumeSnapshots,
+	// and unlocks the writes after.
+	// +kubebuilder:validation:Enum=CrashConsistent;ApplicationConsistent
+	// +optional
+	Consistency SnapshotConsistency `json:"consistency,omitempty"`
+	// WriteLockTimeout specifies the maximum time writes are locked for an ApplicationConsistent snapshot.
+	// If the VolumeSnapshots are not taken in time, the writes are unlocked and the snapshot fails.
+	// Defaults to 5m.
+	// +optional
+	WriteLockTimeout *metav1.Duration `json:"writeLockTimeout,omitempty"`
+}
+
+// SnapshotConsistency specifies the consistency of a VolumeSnapshot
+type SnapshotConsistency string
+
+//goland:noinspection GoUnusedConst
+const (
+	SnapshotCrashConsistent       SnapshotConsistency = "CrashConsistent"
+	SnapshotApplicationConsistent SnapshotConsistency = "ApplicationConsistent"
+)
+
+// DefaultWriteLockTimeout is the default of QdrantClusterSnapshotSpec.WriteLockTimeout
+const DefaultWriteLockTimeout = 5 * time.Minute
+
+// GetConsistency returns the consistency of the snapshot, CrashConsistent if not set.
+func (s QdrantClusterSnapshotSpec) GetConsistency() SnapshotConsistency {
+	if s.Consistency == "" {
+		return SnapshotCrashConsistent
+	}
+	return s.Consistency
+}
+
+// GetWriteLockTimeout returns the write lock timeout, DefaultWriteLockTimeout if not set.
+func (s QdrantClusterSnapshotSpec) GetWriteLockTimeout() time.Duration {
+	if s.WriteLockTimeout == nil {
+		return DefaultWriteLockTimeout
+	}
+	return s.WriteLockTimeout.Duration
+}
+
+// SnapshotMethod specifies how a snapshot is taken
+type SnapshotMethod string
+
+//goland:noinspection GoUnusedConst
+const (
+	SnapshotMethodVolumeSnapshot SnapshotMethod = "VolumeSnapshot"
+	SnapshotMethodQdrantAPI      SnapshotMethod = "QdrantAPI"
+)
+
+// GetMethod returns the snapshot method, VolumeSnapshot if not set.
+func (s QdrantClusterSnapshotSpec) GetMethod() SnapshotMethod {
+	if s.Method == "" {
+		return SnapshotMethodVolumeSnapshot
+	}
+	return s.Method
+}
+
+// Validate validates the consistency, and the snapshot method and destination against the capabilities of the region.
+func (s QdrantClusterSnapshotSpec) Validate(region QdrantCloudRegionStatus) error {
+	if s.GetConsistency() == SnapshotApplicationConsistent && s.GetMethod() != SnapshotMethodVolumeSnapshot {
+		return fmt.Errorf(".spec.consistency: %s is only supported by the %s method", SnapshotApplicationConsistent, SnapshotMethodVolumeSnapshot)
+	}
+	if s.WriteLockTimeout != nil && s.WriteLockTimeout.Duration <= 0 {
+		return fmt.Errorf(".spec.writeLockTimeout: must be positive")
+	}
+	if s.Retention != nil {
+		if _, err := ParseRetention(*s.Retention); err != nil {
+			return fmt.Errorf(".spec.retention error: %w", err)
+		}
+	}
+	return validateSnapshotMethod(s.GetMethod(), s.Collections, s.Destination, region)
 }
 
 type QdrantClusterSnapshotPhase string
@@ -48,6 +145,12 @@ type QdrantClusterSnapshotStatus struct {
 	// VolumeSnapshots is the list of volume snapshots that were created
 	// +optional
 	VolumeSnapshots []VolumeSnapshotInfo `json:"volumeSnapshots,omitempty"`
+	// QdrantSnapshots is the list of snapshot files that were created with the QdrantAPI method
+	// +optional
+	QdrantSnapshots []QdrantSnapshotInfo `json:"qdrantSnapshots,omitempty"`
+	// ExportedObjects is the list of objects the snapshot files were exported to (see Spec.Destination)
+	// +optional
+	ExportedObjects []ExportedObject `json:"exportedObjects,omitempty"`
 	// The calculated time (in UTC) this snapshot will be deleted, if so.
 	// +optional
 	RetainUntil *metav1.Time `json:"retainUntil,omitempty"`
@@ -56,6 +159,15 @@ type QdrantClusterSnapshotStatus struct {
 	// For example: "1d3h5m10s", "3h5m10s", "5m10s", "10s" etc.
 	// +optional
 	CompletionTime *metav1.Duration `json:"completionTime,omitempty"`
+	// WriteLock specifies when and how long writes were locked for an ApplicationConsistent snapshot
+	// +optional
+	WriteLock *WriteLockStatus `json:"writeLock,omitempty"`
+	// ObservedGeneration is the 'Generation' of the resource that was last processed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions specifies the conditions of the resource, including the kstatus compliant Ready, Reconciling and Stalled conditions
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 type VolumeSnapshotInfo struct {
@@ -63,12 +175,22 @@ type VolumeSnapshotInfo struct {
 	VolumeSnapshotName string `json:"volumeSnapshotName"`
 	// VolumeName is the name of the volume that was backed up
 	VolumeName string `json:"volumeName"`
+	// NodeIndex specifies the index of the node the volume belongs to
+	// +optional
+	NodeIndex *int `json:"nodeIndex,omitempty"`
+	// VolumeType specifies which volume of the node was backed up
+	// +kubebuilder:validation:Enum=Database;Snapshots
+	// +optional
+	VolumeType VolumeType `json:"volumeType,omitempty"`
 	// ReadyToUse indicates if the volume snapshot is ready to use
 	// +optional
 	ReadyToUse bool `json:"readyToUse"`
 	// SnapshotHandle is the identifier of the volume snapshot in the respective cloud provider
 	// +optional
 	SnapshotHandle string `json:"snapshotHandle,omitempty"`
+	// RestoreSize is the minimum size of a volume to restore the volume snapshot to
+	// +optional
+	RestoreSize *resource.Quantity `json:"restoreSize,omitempty"`
 	// Error contains the error details if the snapshot creation failed
 	// +optional
 	Error *volumesnapshotv1.VolumeSnapshotError `json:"error,omitempty"`
@@ -78,6 +200,94 @@ type VolumeSnapshotInfo struct {
 	Events []KubernetesEventInfo `json:"events,omitempty"`
 }
 
+// VolumeType specifies which volume of a node is backed up by a VolumeSnapshot
+type VolumeType string
+
+//goland:noinspection GoUnusedConst
+const (
+	VolumeTypeDatabase  VolumeType = "Database"
+	VolumeTypeSnapshots VolumeType = "Snapshots"
+)
+
+// WriteLockStatus specifies when and how long writes were locked for an ApplicationConsistent snapshot
+type WriteLockStatus struct {
+	// LockedAt specifies when the writes were locked
+	LockedAt metav1.MicroTime `json:"lockedAt"`
+	// UnlockedAt specifies when the writes were unlocked, not set while the writes are locked
+	// +optional
+	UnlockedAt *metav1.MicroTime `json:"unlockedAt,omitempty"`
+	// Duration specifies how long the writes were locked
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// RecordWriteLock records the writes are locked at the given time.
+func (s *QdrantClusterSnapshotStatus) RecordWriteLock(now time.Time) {
+	s.WriteLock = &WriteLockStatus{LockedAt: metav1.NewMicroTime(now)}
+}
+
+// RecordWriteUnlock records the writes are unlocked at the given time, including the duration of the lock.
+// It is a no-op if the writes are not locked.
+func (s *QdrantClusterSnapshotStatus) RecordWriteUnlock(now time.Time) {
+	if s.WriteLock == nil || s.WriteLock.UnlockedAt != nil {
+		return
+	}
+	s.WriteLock.UnlockedAt = &metav1.MicroTime{Time: now}
+	s.WriteLock.Duration = &metav1.Duration{Duration: now.Sub(s.WriteLock.LockedAt.Time)}
+}
+
+// IsWriteLockExpired returns true if the writes are still locked after the WriteLockTimeout of the snapshot.
+func (qcs *QdrantClusterSnapshot) IsWriteLockExpired(now time.Time) bool {
+	lock := qcs.Status.WriteLock
+	return lock != nil && lock.UnlockedAt == nil && now.Sub(lock.LockedAt.Time) >= qcs.Spec.GetWriteLockTimeout()
+}
+
+// QdrantSnapshotInfo specifies a snapshot file created with the Qdrant snapshot API
+type QdrantSnapshotInfo struct {
+	// NodeIndex specifies the index of the node the snapshot is taken from
+	NodeIndex int `json:"nodeIndex"`
+	// Collection specifies the name of the collection, empty for a full snapshot
+	// +optional
+	Collection string `json:"collection,omitempty"`
+	// ShardId specifies the id of the shard, if the snapshot contains a single shard
+	// +optional
+	ShardId *int `json:"shardId,omitempty"`
+	// Name specifies the file name of the snapshot
+	Name string `json:"name"`
+	// Size specifies the size of the snapshot file in bytes
+	// +optional
+	Size int64 `json:"size,omitempty"`
+	// Checksum specifies the SHA256 checksum of the snapshot file
+	// +optional
+	Checksum string `json:"checksum,omitempty"`
+	// CreationTime specifies when the snapshot file was created
+	// +optional
+	CreationTime *metav1.Time `json:"creationTime,omitempty"`
+	// Error contains the error details if the snapshot creation failed
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// GetQdrantSnapshotsSize returns the total size in bytes of the snapshot files created with the QdrantAPI method.
+func (s QdrantClusterSnapshotStatus) GetQdrantSnapshotsSize() int64 {
+	var result int64
+	for _, info := range s.QdrantSnapshots {
+		result += info.Size
+	}
+	return result
+}
+
+// GetFailedQdrantSnapshots returns the snapshot files which failed to be created.
+func (s QdrantClusterSnapshotStatus) GetFailedQdrantSnapshots() []QdrantSnapshotInfo {
+	var result []QdrantSnapshotInfo
+	for _, info := range s.QdrantSnapshots {
+		if info.Error != "" {
+			result = append(result, info)
+		}
+	}
+	return result
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=qdrantclustersnapshots,singular=qdrantclustersnapshot,shortName=qcsnap;qcsnaps
 // +kubebuilder:printcolumn:name="clusterid",type=string,JSONPath=`.spec.cluster-id`
@@ -100,6 +310,75 @@ func (qcs *QdrantClusterSnapshot) IsCompleted() bool {
 	return qcs.Status.Phase == SnapshotSucceeded || qcs.Status.Phase == SnapshotFailed || qcs.Status.Phase == SnapshotSkipped
 }
 
+// ParseRetention parses a retention in hours (e.g. "72h"), as used by the Retention of snapshots and schedules.
+func ParseRetention(retention string) (time.Duration, error) {
+	hours, found := strings.CutSuffix(retention, "h")
+	if !found || hours == "" || strings.TrimLeft(hours, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid retention %q, expected hours like \"72h\"", retention)
+	}
+	n, err := strconv.ParseInt(hours, 10, 64)
+	if err != nil || n > int64(math.MaxInt64/time.Hour) {
+		return 0, fmt.Errorf("invalid retention %q, expected hours like \"72h\"", retention)
+	}
+	return time.Duration(n) * time.Hour, nil
+}
+
+// ComputeRetainUntil computes the time the snapshot will be deleted, based on its Retention and GetSnapshotTime.
+// Returns nil if the snapshot doesn't have a retention.
+func (qcs *QdrantClusterSnapshot) ComputeRetainUntil() (*metav1.Time, error) {
+	if qcs.Spec.Retention == nil {
+		return nil, nil
+	}
+	retention, err := ParseRetention(*qcs.Spec.Retention)
+	if err != nil {
+		return nil, fmt.Errorf(".spec.retention error: %w", err)
+	}
+	return &metav1.Time{Time: qcs.GetSnapshotTime().Add(retention)}, nil
+}
+
+// IsExpired returns true if the retention of the snapshot is expired at the given time.
+// Status.RetainUntil is used if set, otherwise it is computed (see ComputeRetainUntil).
+// A snapshot without (valid) retention never expires.
+func (qcs *QdrantClusterSnapshot) IsExpired(now time.Time) bool {
+	retainUntil := qcs.Status.RetainUntil
+	if retainUntil == nil {
+		retainUntil, _ = qcs.ComputeRetainUntil()
+	}
+	return retainUntil != nil && !now.Before(retainUntil.Time)
+}
+
+// ValidateSchedule validates the ScheduleShortId refers to one of the given schedules of the same cluster.
+func (qcs *QdrantClusterSnapshot) ValidateSchedule(schedules *QdrantClusterScheduledSnapshotList) error {
+	id := qcs.Spec.ScheduleShortId
+	if id == nil {
+		return nil
+	}
+	if schedules != nil {
+		for _, s := range schedules.Items {
+			if s.Spec.ClusterId == qcs.Spec.ClusterId && s.Spec.ScheduleShortId == *id {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf(".spec.scheduleShortId: no schedule %q found for cluster %q", *id, qcs.Spec.ClusterId)
+}
+
+// GetKStatus computes the kstatus of the snapshot.
+// If the operator doesn't report the Ready condition (yet), the status is derived from the phase.
+func (qcs *QdrantClusterSnapshot) GetKStatus() kstatus.Result {
+	if r, ok := kstatus.FromConditions(qcs.Generation, qcs.Status.ObservedGeneration, qcs.Status.Conditions); ok {
+		return r
+	}
+	switch qcs.Status.Phase {
+	case SnapshotSucceeded, SnapshotSkipped:
+		return kstatus.Result{Status: kstatus.Current}
+	case SnapshotFailed:
+		return kstatus.Result{Status: kstatus.Failed, Message: "snapshot failed"}
+	default:
+		return kstatus.Result{Status: kstatus.InProgress}
+	}
+}
+
 //+kubebuilder:object:root=true
 
 // QdrantClusterSnapshotList contains a list of QdrantClusterSnapshot
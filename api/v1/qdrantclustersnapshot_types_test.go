@@ -0,0 +1,200 @@
+package v1
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestSnapshotSpecValidate(t *testing.T) {
+	noVolumeSnapshots := QdrantCloudRegionStatus{Capabilities: &RegionCapabilities{VolumeSnapshot: ptr.To(false)}}
+	testCases := []struct {
+		name          string
+		spec          QdrantClusterSnapshotSpec
+		region        QdrantCloudRegionStatus
+		expectedError string
+	}{
+		{name: "Default method", spec: QdrantClusterSnapshotSpec{}},
+		{name: "Qdrant API in region without volume snapshots", spec: QdrantClusterSnapshotSpec{Method: SnapshotMethodQdrantAPI}, region: noVolumeSnapshots},
+		{name: "Per collection snapshot", spec: QdrantClusterSnapshotSpec{Method: SnapshotMethodQdrantAPI, Collections: []string{"products"}}},
+		{
+			name:          "Volume snapshot in region without volume snapshots",
+			spec:          QdrantClusterSnapshotSpec{},
+			region:        noVolumeSnapshots,
+			expectedError: ".spec.method: the region doesn't support volume snapshots, use QdrantAPI instead",
+		},
+		{
+			name:          "Collections with volume snapshot",
+			spec:          QdrantClusterSnapshotSpec{Method: SnapshotMethodVolumeSnapshot, Collections: []string{"products"}},
+			expectedError: ".spec.collections: only supported by the QdrantAPI method",
+		},
+		{
+			name:          "Unknown method",
+			spec:          QdrantClusterSnapshotSpec{Method: "Rsync"},
+			expectedError: `.spec.method: unknown method "Rsync"`,
+		},
+		{name: "Retention", spec: QdrantClusterSnapshotSpec{Retention: ptr.To("2562047h")}},
+		{
+			name:          "Retention out of range",
+			spec:          QdrantClusterSnapshotSpec{Retention: ptr.To("9999999h")},
+			expectedError: `.spec.retention error: invalid retention "9999999h", expected hours like "72h"`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate(tt.region)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestQdrantSnapshotsStatus(t *testing.T) {
+	status := QdrantClusterSnapshotStatus{QdrantSnapshots: []QdrantSnapshotInfo{
+		{NodeIndex: 0, Collection: "products", ShardId: ptr.To(0), Name: "products-0.snapshot", Size: 1024, Checksum: "abc"},
+		{NodeIndex: 1, Collection: "products", ShardId: ptr.To(1), Name: "products-1.snapshot", Size: 2048, Checksum: "def"},
+		{NodeIndex: 2, Collection: "products", ShardId: ptr.To(2), Name: "products-2.snapshot", Error: "timeout"},
+	}}
+	assert.Equal(t, int64(3072), status.GetQdrantSnapshotsSize())
+	assert.Len(t, status.GetFailedQdrantSnapshots(), 1)
+	assert.Equal(t, SnapshotMethodVolumeSnapshot, QdrantClusterSnapshotSpec{}.GetMethod())
+}
+
+func TestParseRetention(t *testing.T) {
+	testCases := []struct {
+		retention     string
+		expected      time.Duration
+		expectedError bool
+	}{
+		{retention: "0h"},
+		{retention: "72h", expected: 72 * time.Hour},
+		{retention: "72", expectedError: true},
+		{retention: "h", expectedError: true},
+		{retention: "1h30m", expectedError: true},
+		{retention: "-1h", expectedError: true},
+		{retention: "99999999999h", expectedError: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.retention, func(t *testing.T) {
+			d, err := ParseRetention(tt.retention)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, d)
+			}
+		})
+	}
+}
+
+func TestSnapshotRetainUntil(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	qcs := &QdrantClusterSnapshot{Spec: QdrantClusterSnapshotSpec{CreationTimestamp: created.Unix()}}
+
+	retainUntil, err := qcs.ComputeRetainUntil()
+	require.NoError(t, err)
+	assert.Nil(t, retainUntil)
+	assert.False(t, qcs.IsExpired(created.AddDate(10, 0, 0)), "no retention, never expires")
+
+	qcs.Spec.Retention = ptr.To("72h")
+	retainUntil, err = qcs.ComputeRetainUntil()
+	require.NoError(t, err)
+	assert.Equal(t, created.Add(72*time.Hour), retainUntil.Time)
+	assert.False(t, qcs.IsExpired(created.Add(71*time.Hour)))
+	assert.True(t, qcs.IsExpired(created.Add(72*time.Hour)))
+
+	// The status takes precedence
+	qcs.Status.RetainUntil = &metav1.Time{Time: created.Add(time.Hour)}
+	assert.True(t, qcs.IsExpired(created.Add(2*time.Hour)))
+
+	qcs.Spec.Retention = ptr.To("3d")
+	_, err = qcs.ComputeRetainUntil()
+	assert.EqualError(t, err, `.spec.retention error: invalid retention "3d", expected hours like "72h"`)
+}
+
+func TestSnapshotValidateSchedule(t *testing.T) {
+	schedules := &QdrantClusterScheduledSnapshotList{Items: []QdrantClusterScheduledSnapshot{
+		{Spec: QdrantClusterScheduledSnapshotSpec{ClusterId: "cluster-1", ScheduleShortId: "daily"}},
+		{Spec: QdrantClusterScheduledSnapshotSpec{ClusterId: "cluster-2", ScheduleShortId: "hourly"}},
+	}}
+	qcs := &QdrantClusterSnapshot{Spec: QdrantClusterSnapshotSpec{ClusterId: "cluster-1"}}
+	assert.NoError(t, qcs.ValidateSchedule(schedules), "manual snapshot")
+
+	qcs.Spec.ScheduleShortId = ptr.To("daily")
+	assert.NoError(t, qcs.ValidateSchedule(schedules))
+
+	qcs.Spec.ScheduleShortId = ptr.To("hourly")
+	assert.EqualError(t, qcs.ValidateSchedule(schedules), `.spec.scheduleShortId: no schedule "hourly" found for cluster "cluster-1"`)
+	assert.EqualError(t, qcs.ValidateSchedule(nil), `.spec.scheduleShortId: no schedule "hourly" found for cluster "cluster-1"`)
+}
+
+func TestSnapshotConsistency(t *testing.T) {
+	testCases := []struct {
+		name          string
+		spec          QdrantClusterSnapshotSpec
+		expectedError string
+	}{
+		{name: "Application consistent volume snapshot", spec: QdrantClusterSnapshotSpec{Consistency: SnapshotApplicationConsistent}},
+		{
+			name:          "Application consistent Qdrant API snapshot",
+			spec:          QdrantClusterSnapshotSpec{Method: SnapshotMethodQdrantAPI, Consistency: SnapshotApplicationConsistent},
+			expectedError: ".spec.consistency: ApplicationConsistent is only supported by the VolumeSnapshot method",
+		},
+		{
+			name:          "Invalid timeout",
+			spec:          QdrantClusterSnapshotSpec{Consistency: SnapshotApplicationConsistent, WriteLockTimeout: &metav1.Duration{}},
+			expectedError: ".spec.writeLockTimeout: must be positive",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate(QdrantCloudRegionStatus{})
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+	assert.Equal(t, SnapshotCrashConsistent, QdrantClusterSnapshotSpec{}.GetConsistency())
+}
+
+func TestSnapshotWriteLock(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	qcs := &QdrantClusterSnapshot{Spec: QdrantClusterSnapshotSpec{
+		Consistency:      SnapshotApplicationConsistent,
+		WriteLockTimeout: &metav1.Duration{Duration: time.Minute},
+	}}
+	assert.False(t, qcs.IsWriteLockExpired(now))
+	qcs.Status.RecordWriteUnlock(now)
+	assert.Nil(t, qcs.Status.WriteLock, "not locked")
+
+	qcs.Status.RecordWriteLock(now)
+	assert.False(t, qcs.IsWriteLockExpired(now.Add(30*time.Second)))
+	assert.True(t, qcs.IsWriteLockExpired(now.Add(time.Minute)))
+
+	qcs.Status.RecordWriteUnlock(now.Add(1500 * time.Millisecond))
+	qcs.Status.RecordWriteUnlock(now.Add(time.Hour))
+	assert.Equal(t, 1500*time.Millisecond, qcs.Status.WriteLock.Duration.Duration)
+	assert.False(t, qcs.IsWriteLockExpired(now.Add(time.Hour)))
+
+	// The lock times keep their sub-second precision when the status is stored
+	data, err := json.Marshal(qcs.Status.WriteLock)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"lockedAt":"2024-01-01T00:00:00.000000Z","unlockedAt":"2024-01-01T00:00:01.500000Z","duration":"1.5s"}`, string(data))
+	var decoded WriteLockStatus
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, decoded.UnlockedAt.Time.Equal(now.Add(1500*time.Millisecond)))
+	assert.Equal(t, decoded.UnlockedAt.Sub(decoded.LockedAt.Time), decoded.Duration.Duration)
+}
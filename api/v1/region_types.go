@@ -4,6 +4,8 @@ import (
 	helmapiv2 "github.com/fluxcd/helm-controller/api/v2"
 	srcapiv1 "github.com/fluxcd/source-controller/api/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/qdrant/kubernetes-api/api/kstatus"
 )
 
 //goland:noinspection GoUnusedConst
@@ -119,6 +121,27 @@ type QdrantCloudRegionStatus struct {
 	// NodeInfos contains the information about the nodes in the Kubernetes cluster
 	// +optional
 	NodeInfos []NodeInfo `json:"nodeInfos,omitempty"`
+	// ObservedGeneration is the 'Generation' of the resource that was last processed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions specifies the conditions of the resource, including the kstatus compliant Ready, Reconciling and Stalled conditions
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// GetStorageClass returns the StorageClass with the given name, or the default StorageClass if name is nil or empty.
+// Returns nil if no such StorageClass is available.
+func (s QdrantCloudRegionStatus) GetStorageClass(name *string) *StorageClass {
+	for i, sc := range s.StorageClasses {
+		if name == nil || *name == "" {
+			if sc.Default {
+				return &s.StorageClasses[i]
+			}
+		} else if sc.Name == *name {
+			return &s.StorageClasses[i]
+		}
+	}
+	return nil
 }
 
 type StorageClass struct {
@@ -266,6 +289,22 @@ type QdrantCloudRegion struct {
 	Status QdrantCloudRegionStatus `json:"status,omitempty"`
 }
 
+// GetKStatus computes the kstatus of the region.
+// If the operator doesn't report the Ready condition (yet), the status is derived from the phase.
+func (r *QdrantCloudRegion) GetKStatus() kstatus.Result {
+	if result, ok := kstatus.FromConditions(r.Generation, r.Status.ObservedGeneration, r.Status.Conditions); ok {
+		return result
+	}
+	switch r.Status.Phase {
+	case RegionPhaseReady:
+		return kstatus.Result{Status: kstatus.Current, Message: r.Status.Message}
+	case FailedToSync:
+		return kstatus.Result{Status: kstatus.Failed, Message: r.Status.Message}
+	default:
+		return kstatus.Result{Status: kstatus.InProgress, Message: r.Status.Message}
+	}
+}
+
 //+kubebuilder:object:root=true
 
 // QdrantCloudRegionList contains a list of QdrantCloudRegion
@@ -0,0 +1,153 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// RestoreClusterTemplate specifies settings of the cluster created by a restore,
+// which override the settings of the cluster the snapshot is taken from.
+// Only the fields which are set are overridden.
+type RestoreClusterTemplate struct {
+	// Version specifies the version of Qdrant to deploy
+	// +optional
+	Version *string `json:"version,omitempty"`
+	// Size specifies the number of Qdrant nodes in the cluster.
+	// Nodes are restored to the same index, so it must be above the highest node index of the snapshot.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Size *int `json:"size,omitempty"`
+	// Resources specifies the resources to allocate for each Qdrant node.
+	// Only the fields which are set override the resources of the cluster.
+	// The storage (of every node pool) must be at least the size of the snapshotted volumes.
+	// +optional
+	Resources *Resources `json:"resources,omitempty"`
+	// StorageClassNames specifies the storage class names for db and snapshots.
+	// Only the fields which are set override the storage class names of the cluster.
+	// +optional
+	StorageClassNames *StorageClassNames `json:"storageClassNames,omitempty"`
+	// Service specifies the configuration of the Qdrant Kubernetes Service.
+	// +optional
+	Service *KubernetesService `json:"service,omitempty"`
+	// Ingress specifies the ingress for the cluster.
+	// +optional
+	Ingress *Ingress `json:"ingress,omitempty"`
+}
+
+// Validate validates the template, the spec of the cluster the snapshot is taken from with the template applied
+// (if the source spec is not nil) and the result against the snapshot to restore from (if not nil).
+// Nodes are restored to the same index, so the size has to cover the highest node index of the snapshot,
+// and the storage of each node pool the node can belong to has to fit the data of the node.
+func (t *RestoreClusterTemplate) Validate(source *QdrantClusterSpec, snapshot *QdrantClusterSnapshot) error {
+	if t == nil {
+		return nil
+	}
+	if t.Version != nil && *t.Version == "" {
+		return errors.New(".spec.destination.template.version: must not be empty")
+	}
+	if err := validatePartialResources(".spec.destination.template.resources", t.Resources); err != nil {
+		return err
+	}
+	if source == nil {
+		return nil
+	}
+	spec := t.Apply(*source)
+	if err := spec.Validate(); err != nil {
+		return fmt.Errorf(".spec.destination.template error: %w", err)
+	}
+	if snapshot == nil {
+		return nil
+	}
+	sizes := snapshot.GetNodeDataSizes()
+	for _, idx := range slices.Sorted(maps.Keys(sizes)) {
+		if idx >= spec.Size {
+			return fmt.Errorf(".spec.destination.template.size: %d nodes can not place node %d of snapshot %s", spec.Size, idx, snapshot.Name)
+		}
+		size := sizes[idx]
+		for _, node := range spec.getNodeSpecs(idx) {
+			storage, err := resource.ParseQuantity(node.Resources.Storage)
+			if err != nil {
+				return fmt.Errorf(".spec.destination.template.resources.storage error: %w", err)
+			}
+			if storage.Cmp(size) < 0 {
+				return fmt.Errorf(".spec.destination.template.resources.storage: %s of node pool %s is less than %s of node %d of snapshot %s", storage.String(), node.NodePool, size.String(), idx, snapshot.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Apply returns the given spec (of the cluster the snapshot is taken from) with the settings of the template applied.
+// NodeOverrides of nodes which don't exist with the new size are dropped.
+// The given spec is not modified.
+func (t *RestoreClusterTemplate) Apply(spec QdrantClusterSpec) QdrantClusterSpec {
+	if t == nil {
+		return spec
+	}
+	if t.Version != nil {
+		spec.Version = *t.Version
+	}
+	if t.Size != nil {
+		spec.Size = *t.Size
+		spec.NodeOverrides = slices.DeleteFunc(slices.Clone(spec.NodeOverrides), func(o NodeOverride) bool {
+			return o.NodeIndex >= spec.Size
+		})
+	}
+	overrideResources(&spec.Resources, t.Resources)
+	if t.StorageClassNames != nil {
+		names := StorageClassNames{}
+		if spec.StorageClassNames != nil {
+			names = *spec.StorageClassNames
+		}
+		if t.StorageClassNames.DB != nil {
+			names.DB = t.StorageClassNames.DB
+		}
+		if t.StorageClassNames.Snapshots != nil {
+			names.Snapshots = t.StorageClassNames.Snapshots
+		}
+		spec.StorageClassNames = &names
+	}
+	if t.Service != nil {
+		spec.Service = t.Service
+	}
+	if t.Ingress != nil {
+		spec.Ingress = t.Ingress
+	}
+	return spec
+}
+
+// GetNodeDataSizes returns the size of the data per node index of the snapshot.
+// For the VolumeSnapshot method this is the restore size of the database volume (0 if unknown),
+// for the QdrantAPI method the total size of the successfully created snapshot files.
+// Volumes without a NodeIndex are ignored.
+func (qcs *QdrantClusterSnapshot) GetNodeDataSizes() map[int]resource.Quantity {
+	result := make(map[int]resource.Quantity)
+	if qcs.Spec.GetMethod() == SnapshotMethodQdrantAPI {
+		bytes := make(map[int]int64)
+		for _, info := range qcs.Status.QdrantSnapshots {
+			if info.Error == "" {
+				bytes[info.NodeIndex] += info.Size
+			}
+		}
+		for idx, size := range bytes {
+			result[idx] = *resource.NewQuantity(size, resource.BinarySI)
+		}
+		return result
+	}
+	for _, info := range qcs.Status.VolumeSnapshots {
+		if info.NodeIndex == nil || info.VolumeType != VolumeTypeDatabase {
+			continue
+		}
+		size := resource.Quantity{}
+		if info.RestoreSize != nil {
+			size = *info.RestoreSize
+		}
+		result[*info.NodeIndex] = size
+	}
+	return result
+}
@@ -0,0 +1,166 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestRestoreClusterTemplateValidate(t *testing.T) {
+	source := &QdrantClusterSpec{
+		Id:        "abc",
+		Version:   "v1.12.0",
+		Size:      6,
+		Resources: Resources{CPU: "1", Memory: "4Gi", Storage: "25Gi"},
+		NodePools: []NodePool{
+			{Name: "big", Size: 2, Resources: &Resources{CPU: "2", Memory: "8Gi", Storage: "30Gi"}},
+			{Name: "empty", Size: 0, Resources: &Resources{CPU: "1", Memory: "4Gi", Storage: "1Gi"}},
+		},
+		NodeOverrides: []NodeOverride{{NodeIndex: 3, Resources: &Resources{Storage: "30Gi"}}},
+	}
+	volumeSnapshot := &QdrantClusterSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "snap"},
+		Spec:       QdrantClusterSnapshotSpec{ClusterId: "abc"},
+		Status: QdrantClusterSnapshotStatus{VolumeSnapshots: []VolumeSnapshotInfo{
+			{VolumeName: "db-0", NodeIndex: ptr.To(0), VolumeType: VolumeTypeDatabase, RestoreSize: ptr.To(resource.MustParse("10Gi"))},
+			{VolumeName: "snapshots-0", NodeIndex: ptr.To(0), VolumeType: VolumeTypeSnapshots, RestoreSize: ptr.To(resource.MustParse("60Gi"))},
+			{VolumeName: "db-3", NodeIndex: ptr.To(3), VolumeType: VolumeTypeDatabase, RestoreSize: ptr.To(resource.MustParse("28Gi"))},
+			{VolumeName: "db-5", NodeIndex: ptr.To(5), VolumeType: VolumeTypeDatabase},
+			{VolumeName: "unknown", RestoreSize: ptr.To(resource.MustParse("100Gi"))},
+		}},
+	}
+	apiSnapshot := &QdrantClusterSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "api"},
+		Spec:       QdrantClusterSnapshotSpec{ClusterId: "abc", Method: SnapshotMethodQdrantAPI},
+		Status: QdrantClusterSnapshotStatus{QdrantSnapshots: []QdrantSnapshotInfo{
+			{NodeIndex: 0, Collection: "a", Size: 1 << 30},
+			{NodeIndex: 0, Collection: "b", Size: 2 << 30},
+			{NodeIndex: 1, Collection: "a", Size: 1 << 30},
+			{NodeIndex: 1, Collection: "b", Error: "failed"},
+		}},
+	}
+	testCases := []struct {
+		name          string
+		template      *RestoreClusterTemplate
+		source        *QdrantClusterSpec
+		snapshot      *QdrantClusterSnapshot
+		expectedError string
+	}{
+		{name: "No template", source: source, snapshot: volumeSnapshot},
+		{name: "Unknown source", template: &RestoreClusterTemplate{Size: ptr.To(1), Resources: &Resources{Storage: "1Gi"}}, snapshot: volumeSnapshot},
+		{name: "Unresolved snapshot", template: &RestoreClusterTemplate{Size: ptr.To(3)}, source: source},
+		{name: "Fits", template: &RestoreClusterTemplate{Version: ptr.To("v1.13.0")}, source: source, snapshot: volumeSnapshot},
+		{name: "Only CPU", template: &RestoreClusterTemplate{Resources: &Resources{CPU: "2"}}, source: source, snapshot: volumeSnapshot},
+		{
+			name:          "Highest node index not covered",
+			template:      &RestoreClusterTemplate{Size: ptr.To(5)},
+			source:        source,
+			snapshot:      volumeSnapshot,
+			expectedError: ".spec.destination.template.size: 5 nodes can not place node 5 of snapshot snap",
+		},
+		{
+			name:          "Size below node pools",
+			template:      &RestoreClusterTemplate{Size: ptr.To(1)},
+			source:        source,
+			expectedError: ".spec.destination.template error: .spec.nodePools: the sum of the pool sizes can not be greater than size (1)",
+		},
+		{
+			name:          "Too small storage",
+			template:      &RestoreClusterTemplate{Resources: &Resources{Storage: "8Gi"}},
+			source:        source,
+			snapshot:      volumeSnapshot,
+			expectedError: ".spec.destination.template.resources.storage: 8Gi of node pool default is less than 10Gi of node 0 of snapshot snap",
+		},
+		{
+			name:     "Too small storage of node pool",
+			template: &RestoreClusterTemplate{Size: ptr.To(6)},
+			source: &QdrantClusterSpec{
+				Id: "abc", Version: "v1.12.0", Size: 6,
+				Resources: Resources{CPU: "1", Memory: "4Gi", Storage: "50Gi"},
+				NodePools: []NodePool{{Name: "small", Size: 1, Resources: &Resources{CPU: "1", Memory: "4Gi", Storage: "5Gi"}}},
+			},
+			snapshot:      volumeSnapshot,
+			expectedError: ".spec.destination.template.resources.storage: 5Gi of node pool small is less than 10Gi of node 0 of snapshot snap",
+		},
+		{name: "API fits", template: &RestoreClusterTemplate{Size: ptr.To(3), Resources: &Resources{Storage: "3Gi"}}, source: source, snapshot: apiSnapshot},
+		{
+			name:          "API too small storage",
+			template:      &RestoreClusterTemplate{Resources: &Resources{Storage: "2Gi"}},
+			source:        source,
+			snapshot:      apiSnapshot,
+			expectedError: ".spec.destination.template.resources.storage: 2Gi of node pool default is less than 3Gi of node 0 of snapshot api",
+		},
+		{
+			name:          "Invalid storage",
+			template:      &RestoreClusterTemplate{Resources: &Resources{Storage: "lots"}},
+			expectedError: ".spec.destination.template.resources.storage error: quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'",
+		},
+		{
+			name:          "Empty version",
+			template:      &RestoreClusterTemplate{Version: ptr.To("")},
+			expectedError: ".spec.destination.template.version: must not be empty",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.template.Validate(tt.source, tt.snapshot)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestRestoreSpecValidateTemplate(t *testing.T) {
+	spec := QdrantClusterRestoreSpec{
+		Source:      RestoreSource{SnapshotName: "snap", Namespace: "qdrant"},
+		Destination: RestoreDestination{Name: "new", Namespace: "qdrant", Template: &RestoreClusterTemplate{Size: ptr.To(3)}},
+	}
+	assert.EqualError(t, spec.Validate(nil, nil), ".spec.destination.template: can only be used if create is true")
+	spec.Destination.Create = true
+	assert.NoError(t, spec.Validate(nil, nil))
+}
+
+func TestRestoreClusterTemplateApply(t *testing.T) {
+	source := QdrantClusterSpec{
+		Id:                "abc",
+		Version:           "v1.12.0",
+		Size:              3,
+		Resources:         Resources{CPU: "1", Memory: "4Gi", Storage: "10Gi"},
+		StorageClassNames: &StorageClassNames{DB: ptr.To("standard"), Snapshots: ptr.To("standard")},
+		NodeOverrides:     []NodeOverride{{NodeIndex: 1, Zone: ptr.To("a")}, {NodeIndex: 4, Zone: ptr.To("b")}},
+	}
+	template := &RestoreClusterTemplate{
+		Version:           ptr.To("v1.13.0"),
+		Size:              ptr.To(5),
+		Resources:         &Resources{Memory: "8Gi", Storage: "20Gi"},
+		StorageClassNames: &StorageClassNames{DB: ptr.To("fast")},
+		Service:           &KubernetesService{Type: "LoadBalancer"},
+	}
+
+	result := template.Apply(source)
+	assert.Equal(t, QdrantClusterSpec{
+		Id:                "abc",
+		Version:           "v1.13.0",
+		Size:              5,
+		Resources:         Resources{CPU: "1", Memory: "8Gi", Storage: "20Gi"},
+		StorageClassNames: &StorageClassNames{DB: ptr.To("fast"), Snapshots: ptr.To("standard")},
+		Service:           &KubernetesService{Type: "LoadBalancer"},
+		NodeOverrides:     []NodeOverride{{NodeIndex: 1, Zone: ptr.To("a")}, {NodeIndex: 4, Zone: ptr.To("b")}},
+	}, result)
+	assert.Len(t, source.NodeOverrides, 2, "source is not modified")
+
+	// Overrides of nodes which don't exist anymore are dropped
+	template.Size = ptr.To(2)
+	assert.Equal(t, []NodeOverride{{NodeIndex: 1, Zone: ptr.To("a")}}, template.Apply(source).NodeOverrides)
+	assert.Equal(t, "standard", *source.StorageClassNames.DB, "source is not modified")
+
+	var none *RestoreClusterTemplate
+	assert.Equal(t, source, none.Apply(source))
+}
@@ -0,0 +1,321 @@
+package v1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SchedulePattern is the pattern QdrantClusterScheduledSnapshotSpec.Schedule is validated with in the CRD,
+// it must be kept in sync with the kubebuilder marker (which is enforced by a test).
+// The pattern isn't anchored as a whole (only the macros at the start and the cron fields at the end),
+// it is kept as is to stay compatible with the stored schedules.
+// ParseSchedule accepts the schedules matching this pattern, as long as the values are in range,
+// except for strings which only match because of the missing anchors and can't be parsed (e.g. "@dailyx").
+const SchedulePattern = `^(@(annually|yearly|monthly|weekly|daily|hourly|reboot))|(@every (\d+(ns|us|µs|ms|s|m|h))+)|((((\d+,)+\d+|([\d\*]+(\/|-)\d+)|\d+|\*) ?){5,7})$`
+
+// maxScheduleYear is the last year a schedule can run in
+const maxScheduleYear = 2099
+
+// Schedule is a parsed schedule of a QdrantClusterScheduledSnapshot, see ParseSchedule
+// +kubebuilder:object:generate=false
+type Schedule struct {
+	// location is set if the schedule specifies its time zone with a CRON_TZ= or TZ= prefix
+	location *time.Location
+	// every is set for @every schedules
+	every time.Duration
+	// reboot is set for the @reboot schedule, which never runs on its own
+	reboot bool
+	// The allowed values of the fields, indexed by value
+	second, minute, hour, dayOfMonth, month, dayOfWeek, year []bool
+	// dayOfMonthAny and dayOfWeekAny are set if the fields are *, see dayMatches
+	dayOfMonthAny, dayOfWeekAny bool
+}
+
+// scheduleField specifies the range of a field of a cron expression
+type scheduleField struct {
+	name     string
+	min, max int
+}
+
+var (
+	secondField     = scheduleField{"second", 0, 59}
+	minuteField     = scheduleField{"minute", 0, 59}
+	hourField       = scheduleField{"hour", 0, 23}
+	dayOfMonthField = scheduleField{"day of month", 1, 31}
+	monthField      = scheduleField{"month", 1, 12}
+	dayOfWeekField  = scheduleField{"day of week", 0, 7}
+	yearField       = scheduleField{"year", 1970, maxScheduleYear}
+)
+
+// scheduleMacros maps the macros to their cron expression
+var scheduleMacros = map[string]string{
+	"@annually": "0 0 1 1 *",
+	"@yearly":   "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseSchedule parses a schedule, which is one of:
+//   - a macro: @annually, @yearly, @monthly, @weekly, @daily, @hourly or @reboot (which never runs on its own)
+//   - @every <duration>, e.g. "@every 1h30m"
+//   - a cron expression with 5 (minute hour day-of-month month day-of-week),
+//     6 (with a leading second) or 7 (with a trailing year) fields.
+//     Each field is a list (1,2,3) of *, a number or a range (1-5), each optionally with a step (*/5, 10/5 or 1-5/2).
+//
+// @every and cron expressions can be prefixed with the time zone to evaluate them in, e.g. "CRON_TZ=Europe/Berlin 0 2 * * *"
+// or "TZ=UTC 0 2 * * *". Like the CRD pattern, a single trailing space is allowed.
+func ParseSchedule(schedule string) (*Schedule, error) {
+	expr := strings.TrimSuffix(schedule, " ")
+	if expr == "@reboot" {
+		return &Schedule{reboot: true}, nil
+	}
+	if expr, found := scheduleMacros[expr]; found {
+		return parseCronExpression(expr, nil)
+	}
+	var loc *time.Location
+	for _, prefix := range []string{"CRON_TZ=", "TZ="} {
+		if rest, found := strings.CutPrefix(expr, prefix); found {
+			tz, rest, _ := strings.Cut(rest, " ")
+			var err error
+			if loc, err = time.LoadLocation(tz); err != nil {
+				return nil, fmt.Errorf("invalid schedule %q: %w", schedule, err)
+			}
+			expr = rest
+			break
+		}
+	}
+	if d, found := strings.CutPrefix(expr, "@every "); found {
+		duration, err := parseEveryDuration(d)
+		if err != nil {
+			return nil, err
+		}
+		return &Schedule{location: loc, every: duration}, nil
+	}
+	result, err := parseCronExpression(expr, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %w", schedule, err)
+	}
+	return result, nil
+}
+
+// parseCronExpression parses a cron expression with 5 to 7 fields, see ParseSchedule.
+func parseCronExpression(expr string, loc *time.Location) (*Schedule, error) {
+	fields := strings.Split(expr, " ")
+	switch len(fields) {
+	case 5:
+		fields = append(append([]string{"0"}, fields...), "*")
+	case 6:
+		fields = append(fields, "*")
+	case 7:
+	default:
+		return nil, fmt.Errorf("expected 5 to 7 fields, got %d", len(fields))
+	}
+	result := &Schedule{location: loc, dayOfMonthAny: fields[3] == "*", dayOfWeekAny: fields[5] == "*"}
+	for i, target := range []struct {
+		field  scheduleField
+		values *[]bool
+	}{
+		{secondField, &result.second},
+		{minuteField, &result.minute},
+		{hourField, &result.hour},
+		{dayOfMonthField, &result.dayOfMonth},
+		{monthField, &result.month},
+		{dayOfWeekField, &result.dayOfWeek},
+		{yearField, &result.year},
+	} {
+		values, err := parseScheduleField(fields[i], target.field)
+		if err != nil {
+			return nil, err
+		}
+		*target.values = values
+	}
+	// Both 0 and 7 are Sunday
+	if result.dayOfWeek[7] {
+		result.dayOfWeek[0] = true
+	}
+	return result, nil
+}
+
+// parseEveryDuration parses the duration of an @every schedule,
+// which is a sequence of numbers with a unit (without fractions or signs).
+func parseEveryDuration(s string) (time.Duration, error) {
+	rest := s
+	for rest != "" {
+		digits := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+		if digits == 0 {
+			return 0, fmt.Errorf("invalid schedule duration %q", s)
+		}
+		rest = rest[digits:]
+		unit := ""
+		for _, u := range []string{"ns", "us", "µs", "ms", "s", "m", "h"} {
+			if strings.HasPrefix(rest, u) {
+				unit = u
+				break
+			}
+		}
+		if unit == "" {
+			return 0, fmt.Errorf("invalid schedule duration %q", s)
+		}
+		rest = rest[len(unit):]
+	}
+	if s == "" {
+		return 0, fmt.Errorf("invalid schedule duration %q", s)
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid schedule duration %q: %w", s, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid schedule duration %q: must be positive", s)
+	}
+	return d, nil
+}
+
+// parseScheduleField parses a field of a cron expression, returning the allowed values indexed by value.
+func parseScheduleField(s string, field scheduleField) ([]bool, error) {
+	values := make([]bool, field.max+1)
+	set := func(from, to, step int) error {
+		if from < field.min || to > field.max || from > to {
+			return fmt.Errorf("%s %q out of range [%d-%d]", field.name, s, field.min, field.max)
+		}
+		for v := from; v <= to; v += step {
+			values[v] = true
+		}
+		return nil
+	}
+	number := func(v string) (int, error) {
+		if v == "" || strings.TrimLeft(v, "0123456789") != "" {
+			return 0, fmt.Errorf("invalid %s %q", field.name, s)
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q", field.name, s)
+		}
+		return n, nil
+	}
+
+	for _, item := range strings.Split(s, ",") {
+		r, stepValue, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := number(stepValue)
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 {
+				return nil, fmt.Errorf("invalid %s %q: step must be positive", field.name, s)
+			}
+			step = n
+		}
+		from, to := field.min, field.max
+		if r != "*" {
+			start, end, isRange := strings.Cut(r, "-")
+			if start != "*" || !isRange {
+				n, err := number(start)
+				if err != nil {
+					return nil, err
+				}
+				from = n
+			}
+			switch {
+			case isRange:
+				n, err := number(end)
+				if err != nil {
+					return nil, err
+				}
+				to = n
+			case !hasStep:
+				// A single value, a value with a step runs from the value to the max
+				to = from
+			}
+		}
+		if err := set(from, to, step); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// NextRun returns the first time after the given time the schedule should run,
+// in the time zone of the schedule if specified, in the location of the given time otherwise.
+// Like cron, runs at local times which don't exist (because of a DST change) are skipped.
+// @every schedules run exactly the duration after the given time, which is not rounded to whole seconds.
+// Returns false if the schedule never runs (anymore), e.g. @reboot or a year in the past.
+func (s *Schedule) NextRun(after time.Time) (time.Time, bool) {
+	if s.reboot {
+		return time.Time{}, false
+	}
+	if s.location != nil {
+		after = after.In(s.location)
+	}
+	if s.every > 0 {
+		return after.Add(s.every), true
+	}
+	loc := after.Location()
+	t := after.Truncate(time.Second).Add(time.Second)
+	// Every iteration moves t forward, so this terminates, the cap is a safety net
+	for i := 0; i < 100000; i++ {
+		switch {
+		case t.Year() > maxScheduleYear:
+			return time.Time{}, false
+		case !s.year[t.Year()]:
+			t = time.Date(t.Year()+1, 1, 1, 0, 0, 0, 0, loc)
+		case !s.month[t.Month()]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !s.hour[t.Hour()]:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !s.minute[t.Minute()]:
+			t = t.Truncate(time.Minute).Add(time.Minute)
+		case !s.second[t.Second()]:
+			t = t.Add(time.Second)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// dayMatches returns true if the day of t matches the day of month and day of week fields.
+// Like cron, if both fields are restricted the day matches if either field matches.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.dayOfMonth[t.Day()]
+	dow := s.dayOfWeek[t.Weekday()]
+	if s.dayOfMonthAny || s.dayOfWeekAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// GetLocation returns the location of the TimeZone, UTC if not set.
+func (s QdrantClusterScheduledSnapshotSpec) GetLocation() (*time.Location, error) {
+	if s.TimeZone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf(".spec.timeZone error: %w", err)
+	}
+	return loc, nil
+}
+
+// NextRun returns the first time after the given time the schedule should run,
+// evaluated in the time zone of the schedule if specified, in the TimeZone of the spec otherwise.
+// Returns false if the schedule never runs (anymore).
+func (s QdrantClusterScheduledSnapshotSpec) NextRun(after time.Time) (time.Time, bool, error) {
+	schedule, err := ParseSchedule(s.Schedule)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf(".spec.schedule error: %w", err)
+	}
+	loc, err := s.GetLocation()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	next, ok := schedule.NextRun(after.In(loc))
+	return next, ok, nil
+}
@@ -0,0 +1,157 @@
+package v1
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// TestSchedulePatternInCRD ensures SchedulePattern is the pattern the CRD validates the schedule with.
+func TestSchedulePatternInCRD(t *testing.T) {
+	f, err := os.Open(filepath.Join("..", "..", "crds", "qdrant.io_qdrantclusterscheduledsnapshots.yaml"))
+	require.NoError(t, err)
+	defer f.Close()
+	var crd apiextensionsv1.CustomResourceDefinition
+	require.NoError(t, yaml.NewYAMLOrJSONDecoder(f, 4096).Decode(&crd))
+	require.Len(t, crd.Spec.Versions, 1)
+	schedule := crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties["schedule"]
+	assert.Equal(t, SchedulePattern, schedule.Pattern)
+}
+
+// TestSchedulePatternAndParserAgree checks the CRD pattern and ParseSchedule agree on a corpus of expressions.
+// Values which are out of range (e.g. minute 60) can't be detected by the pattern, so only the parser rejects those.
+// The pattern isn't anchored as a whole, so it accepts some strings with a prefix or suffix the parser can't make sense of.
+func TestSchedulePatternAndParserAgree(t *testing.T) {
+	pattern := regexp.MustCompile(SchedulePattern)
+	valid := []string{
+		"@annually", "@yearly", "@monthly", "@weekly", "@daily", "@hourly", "@reboot", "@daily ",
+		"@every 1h", "@every 1h30m", "@every 90s", "@every 1500ms", "@every 500ms", "@every 2µs1s", "@every 1h ",
+		"* * * * *", "0 0 * * *", "*/15 * * * *", "0 2 * * 1-5", "0 0 1,15 * *", "5/10 * * * *",
+		"*-5 * * * *", "0 0 0 * * *", "30 0 12 1 1 * 2030", "0 0 1 1 * 7", "0 2 * * * ", "* * * * * ",
+		"1-5/2 * * * *", "1-5,7 * * * *",
+		"CRON_TZ=Europe/Berlin 0 2 * * *", "TZ=UTC 0 2 * * *", "TZ=UTC @every 1h",
+	}
+	invalid := []string{
+		"", " @daily", "x@daily", "@every", "@every ", "@every 1", "@every 1.5h", "@every -1h", "@every 1d",
+		"* * * *", "*  * * * *", "a * * * *", "1, * * * *", "MON * * * *", "0 0 * * MON", "? * * * *", "L * * * *",
+		"0 0 * * *\n", "CRON_TZ=Europe/Berlin @daily",
+	}
+	invalidValues := []string{
+		"60 * * * *", "* 24 * * *", "* * 0 * *", "* * 32 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *",
+		"*/0 * * * *", "70/5 * * * *", "0 0 0 1 1 * 1969", "0 0 0 1 1 * 2100", "@every 0s", "TZ=Mars/Olympus 0 2 * * *",
+	}
+	unanchored := []string{
+		"@dailyx", "@daily whatever", "* * * * * * * *", "*****", "12345", "x 0 2 * * *", "**/5 * * * *", "0 **/5 * * *",
+	}
+	// The parser accepts lists of ranges and steps in every field, the pattern only in the first field
+	parserOnly := []string{"0 1-5/2 * * *", "0 0 * * 1-5,6", "0 */5,7 * * *"}
+
+	for _, s := range valid {
+		assert.True(t, pattern.MatchString(s), "pattern should accept %q", s)
+		_, err := ParseSchedule(s)
+		assert.NoError(t, err, "parser should accept %q", s)
+	}
+	for _, s := range invalid {
+		assert.False(t, pattern.MatchString(s), "pattern should reject %q", s)
+		_, err := ParseSchedule(s)
+		assert.Error(t, err, "parser should reject %q", s)
+	}
+	for _, s := range slices.Concat(invalidValues, unanchored) {
+		assert.True(t, pattern.MatchString(s), "pattern should accept %q", s)
+		_, err := ParseSchedule(s)
+		assert.Error(t, err, "parser should reject %q", s)
+	}
+	for _, s := range parserOnly {
+		assert.False(t, pattern.MatchString(s), "pattern should reject %q", s)
+		_, err := ParseSchedule(s)
+		assert.NoError(t, err, "parser should accept %q", s)
+	}
+}
+
+func TestScheduleNextRun(t *testing.T) {
+	// 2024-01-03 is a Wednesday
+	after := time.Date(2024, 1, 3, 10, 17, 30, 0, time.UTC)
+	testCases := []struct {
+		schedule string
+		expected time.Time
+	}{
+		{"@hourly", time.Date(2024, 1, 3, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 1h30m", time.Date(2024, 1, 3, 11, 47, 30, 0, time.UTC)},
+		{"@every 1500ms", time.Date(2024, 1, 3, 10, 17, 31, int(500*time.Millisecond), time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 3, 10, 30, 0, 0, time.UTC)},
+		{"0 2 * * 1-5", time.Date(2024, 1, 4, 2, 0, 0, 0, time.UTC)},
+		{"0 2 * * 6", time.Date(2024, 1, 6, 2, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches
+		{"0 0 15 * 5", time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"45 17 10 * * *", time.Date(2024, 1, 3, 10, 17, 45, 0, time.UTC)},
+		{"0 0 0 1 1 * 2030", time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0-10/10 * * * *", time.Date(2024, 1, 3, 11, 0, 0, 0, time.UTC)},
+		{"0 2 * * * ", time.Date(2024, 1, 4, 2, 0, 0, 0, time.UTC)},
+		{"CRON_TZ=Europe/Berlin 0 12 * * *", time.Date(2024, 1, 3, 11, 0, 0, 0, time.UTC)},
+		{"TZ=Asia/Kolkata 0 12 * * *", time.Date(2024, 1, 4, 6, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.schedule, func(t *testing.T) {
+			s, err := ParseSchedule(tt.schedule)
+			require.NoError(t, err)
+			next, ok := s.NextRun(after)
+			require.True(t, ok)
+			assert.True(t, tt.expected.Equal(next), "expected %s, got %s", tt.expected, next)
+		})
+	}
+
+	for _, schedule := range []string{"@reboot", "0 0 0 1 1 * 2020"} {
+		s, err := ParseSchedule(schedule)
+		require.NoError(t, err)
+		_, ok := s.NextRun(after)
+		assert.False(t, ok, "%s should never run", schedule)
+	}
+}
+
+func TestScheduledSnapshotNextRunInTimeZone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	spec := QdrantClusterScheduledSnapshotSpec{Schedule: "30 2 * * *", TimeZone: "Europe/Berlin", Retention: "24h"}
+	require.NoError(t, spec.Validate(QdrantCloudRegionStatus{}))
+
+	next, ok, err := spec.NextRun(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.True(t, time.Date(2024, 1, 3, 1, 30, 0, 0, time.UTC).Equal(next))
+
+	// 02:30 doesn't exist on the day DST starts, so that run is skipped
+	next, ok, err = spec.NextRun(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 4, 1, 2, 30, 0, 0, berlin), next)
+
+	// The time zone of the schedule takes precedence
+	spec.Schedule = "CRON_TZ=UTC 30 2 * * *"
+	next, ok, err = spec.NextRun(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.True(t, time.Date(2024, 1, 3, 2, 30, 0, 0, time.UTC).Equal(next))
+
+	spec.Schedule = "30 2 * * *"
+	spec.TimeZone = "Mars/Olympus"
+	assert.EqualError(t, spec.Validate(QdrantCloudRegionStatus{}), ".spec.timeZone error: unknown time zone Mars/Olympus")
+	spec.TimeZone = ""
+	spec.Schedule = "60 * * * *"
+	assert.EqualError(t, spec.Validate(QdrantCloudRegionStatus{}), `.spec.schedule error: invalid schedule "60 * * * *": minute "60" out of range [0-59]`)
+}
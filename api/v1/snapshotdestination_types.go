@@ -0,0 +1,182 @@
+package v1
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+//goland:noinspection GoUnusedConst
+const (
+	// DefaultS3AccessKeyIdKey is the default key of the access key id in the credentials secret
+	DefaultS3AccessKeyIdKey = "accessKeyId"
+	// DefaultS3SecretAccessKeyKey is the default key of the secret access key in the credentials secret
+	DefaultS3SecretAccessKeyKey = "secretAccessKey"
+)
+
+// SnapshotDestination specifies where the snapshot files are exported to.
+// Exporting is only supported for the QdrantAPI snapshot method.
+type SnapshotDestination struct {
+	// S3 specifies an S3 compatible object storage (e.g. AWS S3 or MinIO)
+	// +optional
+	S3 *S3Destination `json:"s3,omitempty"`
+}
+
+// S3Destination specifies a bucket in an S3 compatible object storage
+type S3Destination struct {
+	// Bucket specifies the name of the bucket
+	// +kubebuilder:validation:MinLength=3
+	// +kubebuilder:validation:MaxLength=63
+	Bucket string `json:"bucket"`
+	// Prefix specifies the prefix of the object keys, e.g. "backups/qdrant"
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+	// Endpoint specifies the URL of the object storage, e.g. "http://minio.minio:9000".
+	// If not set, AWS S3 is used.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+	// Region specifies the region of the bucket
+	// +optional
+	Region string `json:"region,omitempty"`
+	// ForcePathStyle specifies whether to use path style URLs (endpoint/bucket/key) instead of
+	// virtual hosted style URLs (bucket.endpoint/key), which is required by most S3 compatible object storages like MinIO.
+	// +optional
+	ForcePathStyle bool `json:"forcePathStyle,omitempty"`
+	// CredentialsSecretRef specifies the secret (in the namespace of the snapshot) containing the credentials.
+	// If not set, the credentials of the environment (e.g. the service account) are used.
+	// +optional
+	CredentialsSecretRef *S3CredentialsSecretRef `json:"credentialsSecretRef,omitempty"`
+	// StorageClass specifies the storage class of the objects, e.g. "STANDARD_IA".
+	// If not set, the default storage class of the bucket is used.
+	// +optional
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// S3CredentialsSecretRef specifies a secret containing S3 credentials
+type S3CredentialsSecretRef struct {
+	// Name specifies the name of the secret
+	Name string `json:"name"`
+	// AccessKeyIdKey specifies the key of the access key id in the secret
+	// +kubebuilder:default=accessKeyId
+	// +optional
+	AccessKeyIdKey string `json:"accessKeyIdKey,omitempty"`
+	// SecretAccessKeyKey specifies the key of the secret access key in the secret
+	// +kubebuilder:default=secretAccessKey
+	// +optional
+	SecretAccessKeyKey string `json:"secretAccessKeyKey,omitempty"`
+}
+
+// ExportedObject specifies a snapshot file exported to the SnapshotDestination
+type ExportedObject struct {
+	// URI specifies the URI of the object, e.g. "s3://bucket/prefix/cluster-id/snapshot/file.snapshot"
+	URI string `json:"uri"`
+	// Size specifies the size of the object in bytes
+	// +optional
+	Size int64 `json:"size,omitempty"`
+}
+
+// Validate validates the destination
+func (d *SnapshotDestination) Validate(base string) error {
+	if d == nil {
+		return nil
+	}
+	if d.S3 == nil {
+		return fmt.Errorf("%s: s3 is required", base)
+	}
+	return d.S3.Validate(base + ".s3")
+}
+
+// Validate validates the S3 destination
+func (d *S3Destination) Validate(base string) error {
+	if d.Bucket == "" || strings.Contains(d.Bucket, "/") {
+		return fmt.Errorf("%s.bucket: invalid bucket name %q", base, d.Bucket)
+	}
+	if strings.HasPrefix(d.Prefix, "/") {
+		return fmt.Errorf("%s.prefix: can not start with /", base)
+	}
+	if d.Endpoint != "" {
+		u, err := url.Parse(d.Endpoint)
+		if err != nil {
+			return fmt.Errorf("%s.endpoint error: %w", base, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s.endpoint: expected a http(s) URL, got %q", base, d.Endpoint)
+		}
+	}
+	if d.CredentialsSecretRef != nil && d.CredentialsSecretRef.Name == "" {
+		return fmt.Errorf("%s.credentialsSecretRef.name: is required", base)
+	}
+	return nil
+}
+
+// GetObjectKey returns the key of the object for the given snapshot file,
+// formatted as <prefix>/<cluster-id>/<snapshot name>/<file name>.
+func (d *S3Destination) GetObjectKey(clusterId, snapshotName, fileName string) string {
+	return path.Join(d.Prefix, clusterId, snapshotName, fileName)
+}
+
+// GetObjectURI returns the s3:// URI of the object with the given key.
+func (d *S3Destination) GetObjectURI(key string) string {
+	return "s3://" + d.Bucket + "/" + key
+}
+
+// GetObjectURL returns the HTTP URL of the object with the given key,
+// respecting the Endpoint and ForcePathStyle settings.
+func (d *S3Destination) GetObjectURL(key string) string {
+	endpoint := d.Endpoint
+	if endpoint == "" {
+		region := d.Region
+		if region == "" {
+			region = "us-east-1"
+		}
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	if d.ForcePathStyle {
+		u.Path = path.Join("/", u.Path, d.Bucket, key)
+	} else {
+		u.Host = d.Bucket + "." + u.Host
+		u.Path = path.Join("/", u.Path, key)
+	}
+	return u.String()
+}
+
+// GetAccessKeyIdKey returns the key of the access key id in the secret
+func (r *S3CredentialsSecretRef) GetAccessKeyIdKey() string {
+	if r == nil || r.AccessKeyIdKey == "" {
+		return DefaultS3AccessKeyIdKey
+	}
+	return r.AccessKeyIdKey
+}
+
+// GetSecretAccessKeyKey returns the key of the secret access key in the secret
+func (r *S3CredentialsSecretRef) GetSecretAccessKeyKey() string {
+	if r == nil || r.SecretAccessKeyKey == "" {
+		return DefaultS3SecretAccessKeyKey
+	}
+	return r.SecretAccessKeyKey
+}
+
+// validateSnapshotMethod validates the snapshot method, collections and destination against the capabilities of the region.
+func validateSnapshotMethod(method SnapshotMethod, collections []string, destination *SnapshotDestination, region QdrantCloudRegionStatus) error {
+	switch method {
+	case SnapshotMethodVolumeSnapshot:
+		if len(collections) > 0 {
+			return fmt.Errorf(".spec.collections: only supported by the %s method", SnapshotMethodQdrantAPI)
+		}
+		if destination != nil {
+			return fmt.Errorf(".spec.destination: only supported by the %s method", SnapshotMethodQdrantAPI)
+		}
+		if c := region.Capabilities; c != nil && c.VolumeSnapshot != nil && !*c.VolumeSnapshot {
+			return fmt.Errorf(".spec.method: the region doesn't support volume snapshots, use %s instead", SnapshotMethodQdrantAPI)
+		}
+	case SnapshotMethodQdrantAPI:
+	default:
+		return fmt.Errorf(".spec.method: unknown method %q", method)
+	}
+	return destination.Validate(".spec.destination")
+}
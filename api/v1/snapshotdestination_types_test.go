@@ -0,0 +1,70 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// minio returns a destination for a local MinIO instance, like used in tests and development setups
+func minio() *S3Destination {
+	return &S3Destination{
+		Bucket:               "snapshots",
+		Prefix:               "qdrant",
+		Endpoint:             "http://localhost:9000",
+		ForcePathStyle:       true,
+		CredentialsSecretRef: &S3CredentialsSecretRef{Name: "minio-credentials"},
+	}
+}
+
+func TestSnapshotDestinationValidate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		destination   *SnapshotDestination
+		expectedError string
+	}{
+		{name: "No destination"},
+		{name: "MinIO", destination: &SnapshotDestination{S3: minio()}},
+		{name: "AWS S3", destination: &SnapshotDestination{S3: &S3Destination{Bucket: "snapshots", Region: "eu-central-1", StorageClass: "STANDARD_IA"}}},
+		{name: "Missing S3", destination: &SnapshotDestination{}, expectedError: ".spec.destination: s3 is required"},
+		{name: "Invalid bucket", destination: &SnapshotDestination{S3: &S3Destination{Bucket: "a/b"}}, expectedError: `.spec.destination.s3.bucket: invalid bucket name "a/b"`},
+		{name: "Absolute prefix", destination: &SnapshotDestination{S3: &S3Destination{Bucket: "snapshots", Prefix: "/qdrant"}}, expectedError: ".spec.destination.s3.prefix: can not start with /"},
+		{name: "Invalid endpoint", destination: &SnapshotDestination{S3: &S3Destination{Bucket: "snapshots", Endpoint: "minio:9000"}}, expectedError: `.spec.destination.s3.endpoint: expected a http(s) URL, got "minio:9000"`},
+		{
+			name:          "Secret without name",
+			destination:   &SnapshotDestination{S3: &S3Destination{Bucket: "snapshots", CredentialsSecretRef: &S3CredentialsSecretRef{}}},
+			expectedError: ".spec.destination.s3.credentialsSecretRef.name: is required",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := QdrantClusterSnapshotSpec{Method: SnapshotMethodQdrantAPI, Destination: tt.destination}
+			err := spec.Validate(QdrantCloudRegionStatus{})
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+
+	// Volume snapshots can't be exported
+	err := QdrantClusterScheduledSnapshotSpec{Schedule: "@daily", Retention: "72h", Destination: &SnapshotDestination{S3: minio()}}.Validate(QdrantCloudRegionStatus{})
+	assert.EqualError(t, err, ".spec.destination: only supported by the QdrantAPI method")
+	assert.NoError(t, QdrantClusterScheduledSnapshotSpec{Schedule: "@daily", Retention: "72h", Method: SnapshotMethodQdrantAPI, Destination: &SnapshotDestination{S3: minio()}}.Validate(QdrantCloudRegionStatus{}))
+}
+
+func TestS3DestinationObjects(t *testing.T) {
+	d := minio()
+	key := d.GetObjectKey("cluster-1", "snapshot-1", "node-0.snapshot")
+	assert.Equal(t, "qdrant/cluster-1/snapshot-1/node-0.snapshot", key)
+	assert.Equal(t, "s3://snapshots/qdrant/cluster-1/snapshot-1/node-0.snapshot", d.GetObjectURI(key))
+	assert.Equal(t, "http://localhost:9000/snapshots/qdrant/cluster-1/snapshot-1/node-0.snapshot", d.GetObjectURL(key))
+	assert.Equal(t, DefaultS3AccessKeyIdKey, d.CredentialsSecretRef.GetAccessKeyIdKey())
+	assert.Equal(t, DefaultS3SecretAccessKeyKey, d.CredentialsSecretRef.GetSecretAccessKeyKey())
+
+	aws := &S3Destination{Bucket: "snapshots", Region: "eu-central-1"}
+	assert.Equal(t, "snapshot-1/file", aws.GetObjectKey("", "snapshot-1", "file"))
+	assert.Equal(t, "https://snapshots.s3.eu-central-1.amazonaws.com/snapshot-1/file", aws.GetObjectURL("snapshot-1/file"))
+}
@@ -0,0 +1,198 @@
+package v1
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// SnapshotRetentionPolicy specifies which successful snapshots to keep, e.g. the last 7 daily, 4 weekly and 12 monthly snapshots.
+// A snapshot is kept if it is kept by any of the rules. The most recent successful snapshot is always kept.
+type SnapshotRetentionPolicy struct {
+	// KeepLast specifies the number of most recent successful snapshots to keep, whatever their age
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepLast *int `json:"keepLast,omitempty"`
+	// KeepDaily specifies the number of days to keep the most recent successful snapshot for
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepDaily *int `json:"keepDaily,omitempty"`
+	// KeepWeekly specifies the number of (ISO) weeks to keep the most recent successful snapshot for
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepWeekly *int `json:"keepWeekly,omitempty"`
+	// KeepMonthly specifies the number of months to keep the most recent successful snapshot for
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepMonthly *int `json:"keepMonthly,omitempty"`
+	// KeepYearly specifies the number of years to keep the most recent successful snapshot for
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepYearly *int `json:"keepYearly,omitempty"`
+}
+
+// Validate validates the retention policy
+func (p *SnapshotRetentionPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	for _, rule := range []struct {
+		name  string
+		value *int
+	}{
+		{"keepLast", p.KeepLast},
+		{"keepDaily", p.KeepDaily},
+		{"keepWeekly", p.KeepWeekly},
+		{"keepMonthly", p.KeepMonthly},
+		{"keepYearly", p.KeepYearly},
+	} {
+		if rule.value != nil && *rule.value < 0 {
+			return fmt.Errorf(".spec.retentionPolicy.%s: must not be negative", rule.name)
+		}
+	}
+	return nil
+}
+
+// GetSnapshotTime returns the time the snapshot is taken,
+// the CreationTimestamp of the spec if set, the creation time of the resource otherwise.
+func (qcs *QdrantClusterSnapshot) GetSnapshotTime() time.Time {
+	if qcs.Spec.CreationTimestamp > 0 {
+		return time.Unix(qcs.Spec.CreationTimestamp, 0).UTC()
+	}
+	return qcs.CreationTimestamp.UTC()
+}
+
+// PruneSnapshots returns the snapshots of the list to delete according to the policy (nil if none is set)
+// and the age based retention (0 if none is set) at the given time.
+// Snapshots which are not completed yet, or are younger than the retention, are never pruned.
+// The most recent successful snapshot is never pruned, so the only successful snapshot is never pruned.
+// The caller is responsible for passing the snapshots of a single cluster (and schedule).
+func PruneSnapshots(list *QdrantClusterSnapshotList, policy *SnapshotRetentionPolicy, retention time.Duration, now time.Time) []QdrantClusterSnapshot {
+	if list == nil {
+		return nil
+	}
+	snapshots := slices.Clone(list.Items)
+	// Most recent first
+	slices.SortStableFunc(snapshots, func(a, b QdrantClusterSnapshot) int {
+		return b.GetSnapshotTime().Compare(a.GetSnapshotTime())
+	})
+	keep := make([]bool, len(snapshots))
+	var succeeded []int
+	for i := range snapshots {
+		s := &snapshots[i]
+		if !s.IsCompleted() || (retention > 0 && now.Sub(s.GetSnapshotTime()) < retention) {
+			keep[i] = true
+		}
+		if s.Status.Phase == SnapshotSucceeded {
+			succeeded = append(succeeded, i)
+		}
+	}
+	if len(succeeded) > 0 {
+		keep[succeeded[0]] = true
+	}
+	if policy != nil {
+		keepLast := 0
+		if policy.KeepLast != nil {
+			keepLast = *policy.KeepLast
+		}
+		for _, i := range succeeded[:min(keepLast, len(succeeded))] {
+			keep[i] = true
+		}
+		for _, rule := range []struct {
+			count  *int
+			bucket func(t time.Time) int
+		}{
+			{policy.KeepDaily, func(t time.Time) int { y, d := t.Year(), t.YearDay(); return y*1000 + d }},
+			{policy.KeepWeekly, func(t time.Time) int { y, w := t.ISOWeek(); return y*100 + w }},
+			{policy.KeepMonthly, func(t time.Time) int { return t.Year()*100 + int(t.Month()) }},
+			{policy.KeepYearly, func(t time.Time) int { return t.Year() }},
+		} {
+			if rule.count == nil {
+				continue
+			}
+			// Keep the most recent snapshot in each of the last count buckets
+			kept, last := 0, 0
+			for _, i := range succeeded {
+				if kept >= *rule.count {
+					break
+				}
+				if b := rule.bucket(snapshots[i].GetSnapshotTime()); kept == 0 || b != last {
+					keep[i] = true
+					kept++
+					last = b
+				}
+			}
+		}
+	}
+	var result []QdrantClusterSnapshot
+	for i := range snapshots {
+		if !keep[i] {
+			result = append(result, snapshots[i])
+		}
+	}
+	return result
+}
+
+// GetSnapshotsToPrune returns the snapshots created by this schedule which should be deleted at the given time,
+// according to the Retention and RetentionPolicy (see PruneSnapshots) and the history limits.
+// The list may contain snapshots of other clusters and schedules, which are ignored.
+func (qcss *QdrantClusterScheduledSnapshot) GetSnapshotsToPrune(list *QdrantClusterSnapshotList, now time.Time) ([]QdrantClusterSnapshot, error) {
+	retention, err := ParseRetention(qcss.Spec.Retention)
+	if err != nil {
+		return nil, fmt.Errorf(".spec.retention error: %w", err)
+	}
+	owned := &QdrantClusterSnapshotList{}
+	for _, s := range list.Items {
+		if s.Spec.ClusterId == qcss.Spec.ClusterId && s.Spec.ScheduleShortId != nil && *s.Spec.ScheduleShortId == qcss.Spec.ScheduleShortId {
+			owned.Items = append(owned.Items, s)
+		}
+	}
+	pruned := PruneSnapshots(owned, qcss.Spec.RetentionPolicy, retention, now)
+	return applyHistoryLimits(owned, pruned, qcss.Spec.SuccessfulSnapshotsHistoryLimit, qcss.Spec.FailedSnapshotsHistoryLimit), nil
+}
+
+// GetSnapshotRetention returns the Retention to set on the snapshots created by this schedule.
+// If a RetentionPolicy is set, the snapshots don't get a retention (nil), because the policy may keep them
+// longer than the Retention: they are only deleted when pruned by GetSnapshotsToPrune, so they never expire on their own
+// (see QdrantClusterSnapshot.IsExpired).
+func (qcss *QdrantClusterScheduledSnapshot) GetSnapshotRetention() *string {
+	if qcss.Spec.RetentionPolicy != nil {
+		return nil
+	}
+	retention := qcss.Spec.Retention
+	return &retention
+}
+
+// applyHistoryLimits adds the oldest snapshots which are not pruned yet to the pruned snapshots,
+// until the number of kept successful and failed (or skipped) snapshots is within the given limits (nil for no limit).
+// The most recent successful snapshot is never pruned.
+func applyHistoryLimits(list *QdrantClusterSnapshotList, pruned []QdrantClusterSnapshot, successfulLimit, failedLimit *int) []QdrantClusterSnapshot {
+	if successfulLimit == nil && failedLimit == nil {
+		return pruned
+	}
+	isPruned := make(map[string]bool, len(pruned))
+	for _, s := range pruned {
+		isPruned[s.Name] = true
+	}
+	kept := slices.DeleteFunc(slices.Clone(list.Items), func(s QdrantClusterSnapshot) bool { return isPruned[s.Name] })
+	// Most recent first
+	slices.SortStableFunc(kept, func(a, b QdrantClusterSnapshot) int {
+		return b.GetSnapshotTime().Compare(a.GetSnapshotTime())
+	})
+	successful, failed := 0, 0
+	for _, s := range kept {
+		switch {
+		case s.Status.Phase == SnapshotSucceeded:
+			successful++
+			if successfulLimit != nil && successful > max(*successfulLimit, 1) {
+				pruned = append(pruned, s)
+			}
+		case s.IsCompleted():
+			failed++
+			if failedLimit != nil && failed > *failedLimit {
+				pruned = append(pruned, s)
+			}
+		}
+	}
+	return pruned
+}
@@ -0,0 +1,121 @@
+package v1
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// dailySnapshots returns a snapshot per day, for the given number of days before now, with the given phase
+func dailySnapshots(now time.Time, days int, phase QdrantClusterSnapshotPhase) *QdrantClusterSnapshotList {
+	list := &QdrantClusterSnapshotList{}
+	for i := 0; i < days; i++ {
+		list.Items = append(list.Items, QdrantClusterSnapshot{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("snapshot-%d", i)},
+			Spec: QdrantClusterSnapshotSpec{
+				ClusterId:         "cluster-1",
+				ScheduleShortId:   ptr.To("daily"),
+				CreationTimestamp: now.AddDate(0, 0, -i).Unix(),
+			},
+			Status: QdrantClusterSnapshotStatus{Phase: phase},
+		})
+	}
+	return list
+}
+
+func snapshotNames(snapshots []QdrantClusterSnapshot) []string {
+	var result []string
+	for _, s := range snapshots {
+		result = append(result, s.Name)
+	}
+	slices.Sort(result)
+	return result
+}
+
+func TestPruneSnapshots(t *testing.T) {
+	now := time.Date(2024, 6, 15, 3, 0, 0, 0, time.UTC)
+
+	t.Run("Age based retention", func(t *testing.T) {
+		pruned := PruneSnapshots(dailySnapshots(now, 5, SnapshotSucceeded), nil, 72*time.Hour, now)
+		assert.Equal(t, []string{"snapshot-3", "snapshot-4"}, snapshotNames(pruned))
+	})
+
+	t.Run("Keep last", func(t *testing.T) {
+		pruned := PruneSnapshots(dailySnapshots(now, 5, SnapshotSucceeded), &SnapshotRetentionPolicy{KeepLast: ptr.To(2)}, 0, now)
+		assert.Equal(t, []string{"snapshot-2", "snapshot-3", "snapshot-4"}, snapshotNames(pruned))
+	})
+
+	t.Run("GFS", func(t *testing.T) {
+		list := dailySnapshots(now, 400, SnapshotSucceeded)
+		policy := &SnapshotRetentionPolicy{KeepDaily: ptr.To(7), KeepWeekly: ptr.To(4), KeepMonthly: ptr.To(12)}
+		pruned := PruneSnapshots(list, policy, 0, now)
+		kept := len(list.Items) - len(pruned)
+		// 2024-06-15 is a Saturday, so the daily snapshots (06-09..06-15) cover the last 2 weeks,
+		// the weekly snapshots add 06-02 and 05-26, the monthly snapshots add the last day of the 11 previous months
+		assert.Equal(t, 7+2+11, kept)
+		for _, s := range pruned {
+			assert.NotEqual(t, "snapshot-0", s.Name)
+		}
+	})
+
+	t.Run("Never prune the only successful snapshot", func(t *testing.T) {
+		list := dailySnapshots(now, 5, SnapshotFailed)
+		list.Items[3].Status.Phase = SnapshotSucceeded
+		pruned := PruneSnapshots(list, &SnapshotRetentionPolicy{KeepLast: ptr.To(0)}, time.Hour, now)
+		assert.Equal(t, []string{"snapshot-1", "snapshot-2", "snapshot-4"}, snapshotNames(pruned))
+	})
+
+	t.Run("Never prune running snapshots", func(t *testing.T) {
+		list := dailySnapshots(now, 3, SnapshotRunning)
+		assert.Empty(t, PruneSnapshots(list, &SnapshotRetentionPolicy{}, 0, now))
+	})
+}
+
+func TestGetSnapshotsToPrune(t *testing.T) {
+	now := time.Date(2024, 6, 15, 3, 0, 0, 0, time.UTC)
+	list := dailySnapshots(now, 3, SnapshotSucceeded)
+	other := list.Items[2].DeepCopy()
+	other.Name = "other-cluster"
+	other.Spec.ClusterId = "cluster-2"
+	list.Items = append(list.Items, *other)
+
+	qcss := &QdrantClusterScheduledSnapshot{Spec: QdrantClusterScheduledSnapshotSpec{ClusterId: "cluster-1", ScheduleShortId: "daily", Schedule: "@daily", Retention: "30h"}}
+	pruned, err := qcss.GetSnapshotsToPrune(list, now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"snapshot-2"}, snapshotNames(pruned))
+
+	qcss.Spec.RetentionPolicy = &SnapshotRetentionPolicy{KeepLast: ptr.To(-1)}
+	assert.EqualError(t, qcss.Spec.Validate(QdrantCloudRegionStatus{}), ".spec.retentionPolicy.keepLast: must not be negative")
+}
+
+func TestRetentionPolicyWinsOverSnapshotRetention(t *testing.T) {
+	now := time.Date(2024, 6, 15, 3, 0, 0, 0, time.UTC)
+	list := dailySnapshots(now, 40, SnapshotSucceeded)
+	qcss := &QdrantClusterScheduledSnapshot{Spec: QdrantClusterScheduledSnapshotSpec{
+		ClusterId: "cluster-1", ScheduleShortId: "daily", Schedule: "@daily", Retention: "72h",
+		RetentionPolicy: &SnapshotRetentionPolicy{KeepMonthly: ptr.To(2)},
+	}}
+	for i := range list.Items {
+		list.Items[i].Spec.Retention = qcss.GetSnapshotRetention()
+	}
+	pruned, err := qcss.GetSnapshotsToPrune(list, now)
+	require.NoError(t, err)
+	require.NotEmpty(t, pruned)
+	// The snapshot of the 31st of May is kept by the policy, so it doesn't expire on its own
+	monthly := list.Items[15]
+	assert.NotContains(t, snapshotNames(pruned), monthly.Name)
+	assert.Nil(t, monthly.Spec.Retention)
+	assert.False(t, monthly.IsExpired(now))
+
+	// Without a policy, the snapshots expire after the Retention
+	qcss.Spec.RetentionPolicy = nil
+	monthly.Spec.Retention = qcss.GetSnapshotRetention()
+	assert.Equal(t, "72h", *monthly.Spec.Retention)
+	assert.True(t, monthly.IsExpired(now))
+}
@@ -12,6 +12,7 @@ import (
 	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -44,6 +45,114 @@ func (in *AuditConfig) DeepCopy() *AuditConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Autoscaling) DeepCopyInto(out *Autoscaling) {
+	*out = *in
+	if in.Vertical != nil {
+		in, out := &in.Vertical, &out.Vertical
+		*out = new(VerticalAutoscaling)
+		**out = **in
+	}
+	if in.TargetMemoryUtilization != nil {
+		in, out := &in.TargetMemoryUtilization, &out.TargetMemoryUtilization
+		*out = new(int)
+		**out = **in
+	}
+	if in.TargetDiskUtilization != nil {
+		in, out := &in.TargetDiskUtilization, &out.TargetDiskUtilization
+		*out = new(int)
+		**out = **in
+	}
+	if in.ScaleUpStabilizationWindow != nil {
+		in, out := &in.ScaleUpStabilizationWindow, &out.ScaleUpStabilizationWindow
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ScaleDownStabilizationWindow != nil {
+		in, out := &in.ScaleDownStabilizationWindow, &out.ScaleDownStabilizationWindow
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Autoscaling.
+func (in *Autoscaling) DeepCopy() *Autoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(Autoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingResources) DeepCopyInto(out *AutoscalingResources) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingResources.
+func (in *AutoscalingResources) DeepCopy() *AutoscalingResources {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingStatus) DeepCopyInto(out *AutoscalingStatus) {
+	*out = *in
+	if in.LastDecisionTime != nil {
+		in, out := &in.LastDecisionTime, &out.LastDecisionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DesiredResources != nil {
+		in, out := &in.DesiredResources, &out.DesiredResources
+		*out = new(AutoscalingResources)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingStatus.
+func (in *AutoscalingStatus) DeepCopy() *AutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryUpdate) DeepCopyInto(out *CanaryUpdate) {
+	*out = *in
+	if in.NodeIndex != nil {
+		in, out := &in.NodeIndex, &out.NodeIndex
+		*out = new(int)
+		**out = **in
+	}
+	if in.SoakTime != nil {
+		in, out := &in.SoakTime, &out.SoakTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryUpdate.
+func (in *CanaryUpdate) DeepCopy() *CanaryUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterManagerReponse) DeepCopyInto(out *ClusterManagerReponse) {
 	*out = *in
@@ -59,6 +168,59 @@ func (in *ClusterManagerReponse) DeepCopy() *ClusterManagerReponse {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CollectionStatus) DeepCopyInto(out *CollectionStatus) {
+	*out = *in
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make([]ShardStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CollectionStatus.
+func (in *CollectionStatus) DeepCopy() *CollectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CollectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CollectionsStatus) DeepCopyInto(out *CollectionsStatus) {
+	*out = *in
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CollectionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ShardTransfers != nil {
+		in, out := &in.ShardTransfers, &out.ShardTransfers
+		*out = make([]ShardTransferStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CollectionsStatus.
+func (in *CollectionsStatus) DeepCopy() *CollectionsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CollectionsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentReference) DeepCopyInto(out *ComponentReference) {
 	*out = *in
@@ -89,6 +251,41 @@ func (in *ComponentStatus) DeepCopy() *ComponentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeferredOperation) DeepCopyInto(out *DeferredOperation) {
+	*out = *in
+	in.DeferredSince.DeepCopyInto(&out.DeferredSince)
+	if in.ScheduledAt != nil {
+		in, out := &in.ScheduledAt, &out.ScheduledAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeferredOperation.
+func (in *DeferredOperation) DeepCopy() *DeferredOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(DeferredOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportedObject) DeepCopyInto(out *ExportedObject) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportedObject.
+func (in *ExportedObject) DeepCopy() *ExportedObject {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportedObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GPU) DeepCopyInto(out *GPU) {
 	*out = *in
@@ -333,6 +530,26 @@ func (in *KubernetesStatefulSet) DeepCopy() *KubernetesStatefulSet {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]Weekday, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Monitoring) DeepCopyInto(out *Monitoring) {
 	*out = *in
@@ -373,6 +590,29 @@ func (in *NGINXConfig) DeepCopy() *NGINXConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeDecommissionStatus) DeepCopyInto(out *NodeDecommissionStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDecommissionStatus.
+func (in *NodeDecommissionStatus) DeepCopy() *NodeDecommissionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeDecommissionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeInfo) DeepCopyInto(out *NodeInfo) {
 	*out = *in
@@ -390,6 +630,38 @@ func (in *NodeInfo) DeepCopy() *NodeInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeOverride) DeepCopyInto(out *NodeOverride) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(Resources)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Zone != nil {
+		in, out := &in.Zone, &out.Zone
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeOverride.
+func (in *NodeOverride) DeepCopy() *NodeOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodePVCStatus) DeepCopyInto(out *NodePVCStatus) {
 	*out = *in
@@ -436,6 +708,65 @@ func (in *NodePVCStatus) DeepCopy() *NodePVCStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePool) DeepCopyInto(out *NodePool) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(Resources)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GPU != nil {
+		in, out := &in.GPU, &out.GPU
+		*out = new(GPU)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageClassNames != nil {
+		in, out := &in.StorageClassNames, &out.StorageClassNames
+		*out = new(StorageClassNames)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePool.
+func (in *NodePool) DeepCopy() *NodePool {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolStatus) DeepCopyInto(out *NodePoolStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolStatus.
+func (in *NodePoolStatus) DeepCopy() *NodePoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeResourceInfo) DeepCopyInto(out *NodeResourceInfo) {
 	*out = *in
@@ -451,6 +782,46 @@ func (in *NodeResourceInfo) DeepCopy() *NodeResourceInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSpec) DeepCopyInto(out *NodeSpec) {
+	*out = *in
+	out.Resources = in.Resources
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GPU != nil {
+		in, out := &in.GPU, &out.GPU
+		*out = new(GPU)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageClassNames != nil {
+		in, out := &in.StorageClassNames, &out.StorageClassNames
+		*out = new(StorageClassNames)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSpec.
+func (in *NodeSpec) DeepCopy() *NodeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeStatus) DeepCopyInto(out *NodeStatus) {
 	*out = *in
@@ -487,6 +858,20 @@ func (in *NodeStatus) DeepCopyInto(out *NodeStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastSeen != nil {
+		in, out := &in.LastSeen, &out.LastSeen
+		*out = (*in).DeepCopy()
+	}
+	if in.DatabaseDiskUsage != nil {
+		in, out := &in.DatabaseDiskUsage, &out.DatabaseDiskUsage
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MemoryWorkingSet != nil {
+		in, out := &in.MemoryWorkingSet, &out.MemoryWorkingSet
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 	in.DatabasePVCStatus.DeepCopyInto(&out.DatabasePVCStatus)
 	in.SnapshotsPVCStatus.DeepCopyInto(&out.SnapshotsPVCStatus)
 }
@@ -501,9 +886,60 @@ func (in *NodeStatus) DeepCopy() *NodeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationRequest) DeepCopyInto(out *OperationRequest) {
+	*out = *in
+	if in.NodeIndexes != nil {
+		in, out := &in.NodeIndexes, &out.NodeIndexes
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationRequest.
+func (in *OperationRequest) DeepCopy() *OperationRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationStatus) DeepCopyInto(out *OperationStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationStatus.
+func (in *OperationStatus) DeepCopy() *OperationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Pause) DeepCopyInto(out *Pause) {
 	*out = *in
+	if in.CreatedAt != nil {
+		in, out := &in.CreatedAt, &out.CreatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pause.
@@ -533,6 +969,22 @@ func (in *PersistentVolumeClaimTemplate) DeepCopy() *PersistentVolumeClaimTempla
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PhaseTransition) DeepCopyInto(out *PhaseTransition) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhaseTransition.
+func (in *PhaseTransition) DeepCopy() *PhaseTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(PhaseTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *QdrantCloudRegion) DeepCopyInto(out *QdrantCloudRegion) {
 	*out = *in
@@ -669,6 +1121,13 @@ func (in *QdrantCloudRegionStatus) DeepCopyInto(out *QdrantCloudRegionStatus) {
 		*out = make([]NodeInfo, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QdrantCloudRegionStatus.
@@ -745,7 +1204,7 @@ func (in *QdrantClusterRestore) DeepCopyInto(out *QdrantClusterRestore) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -802,8 +1261,8 @@ func (in *QdrantClusterRestoreList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *QdrantClusterRestoreSpec) DeepCopyInto(out *QdrantClusterRestoreSpec) {
 	*out = *in
-	out.Source = in.Source
-	out.Destination = in.Destination
+	in.Source.DeepCopyInto(&out.Source)
+	in.Destination.DeepCopyInto(&out.Destination)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QdrantClusterRestoreSpec.
@@ -824,6 +1283,18 @@ func (in *QdrantClusterRestoreStatus) DeepCopyInto(out *QdrantClusterRestoreStat
 		*out = new(string)
 		**out = **in
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Snapshot != nil {
+		in, out := &in.Snapshot, &out.Snapshot
+		*out = new(ResolvedSnapshot)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QdrantClusterRestoreStatus.
@@ -841,7 +1312,7 @@ func (in *QdrantClusterScheduledSnapshot) DeepCopyInto(out *QdrantClusterSchedul
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -898,6 +1369,31 @@ func (in *QdrantClusterScheduledSnapshotList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *QdrantClusterScheduledSnapshotSpec) DeepCopyInto(out *QdrantClusterScheduledSnapshotSpec) {
 	*out = *in
+	if in.RetentionPolicy != nil {
+		in, out := &in.RetentionPolicy, &out.RetentionPolicy
+		*out = new(SnapshotRetentionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Destination != nil {
+		in, out := &in.Destination, &out.Destination
+		*out = new(SnapshotDestination)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SuccessfulSnapshotsHistoryLimit != nil {
+		in, out := &in.SuccessfulSnapshotsHistoryLimit, &out.SuccessfulSnapshotsHistoryLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.FailedSnapshotsHistoryLimit != nil {
+		in, out := &in.FailedSnapshotsHistoryLimit, &out.FailedSnapshotsHistoryLimit
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QdrantClusterScheduledSnapshotSpec.
@@ -919,6 +1415,26 @@ func (in *QdrantClusterScheduledSnapshotStatus) DeepCopyInto(out *QdrantClusterS
 		*out = new(string)
 		**out = **in
 	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]corev1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QdrantClusterScheduledSnapshotStatus.
@@ -1003,6 +1519,21 @@ func (in *QdrantClusterSnapshotSpec) DeepCopyInto(out *QdrantClusterSnapshotSpec
 		*out = new(string)
 		**out = **in
 	}
+	if in.Collections != nil {
+		in, out := &in.Collections, &out.Collections
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Destination != nil {
+		in, out := &in.Destination, &out.Destination
+		*out = new(SnapshotDestination)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WriteLockTimeout != nil {
+		in, out := &in.WriteLockTimeout, &out.WriteLockTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QdrantClusterSnapshotSpec.
@@ -1025,6 +1556,18 @@ func (in *QdrantClusterSnapshotStatus) DeepCopyInto(out *QdrantClusterSnapshotSt
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.QdrantSnapshots != nil {
+		in, out := &in.QdrantSnapshots, &out.QdrantSnapshots
+		*out = make([]QdrantSnapshotInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExportedObjects != nil {
+		in, out := &in.ExportedObjects, &out.ExportedObjects
+		*out = make([]ExportedObject, len(*in))
+		copy(*out, *in)
+	}
 	if in.RetainUntil != nil {
 		in, out := &in.RetainUntil, &out.RetainUntil
 		*out = (*in).DeepCopy()
@@ -1034,6 +1577,18 @@ func (in *QdrantClusterSnapshotStatus) DeepCopyInto(out *QdrantClusterSnapshotSt
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.WriteLock != nil {
+		in, out := &in.WriteLock, &out.WriteLock
+		*out = new(WriteLockStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QdrantClusterSnapshotStatus.
@@ -1062,7 +1617,9 @@ func (in *QdrantClusterSpec) DeepCopyInto(out *QdrantClusterSpec) {
 	if in.Pauses != nil {
 		in, out := &in.Pauses, &out.Pauses
 		*out = make([]Pause, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Image != nil {
 		in, out := &in.Image, &out.Image
@@ -1145,6 +1702,11 @@ func (in *QdrantClusterSpec) DeepCopyInto(out *QdrantClusterSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(UpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.StartupDelaySeconds != nil {
 		in, out := &in.StartupDelaySeconds, &out.StartupDelaySeconds
 		*out = new(int)
@@ -1165,6 +1727,49 @@ func (in *QdrantClusterSpec) DeepCopyInto(out *QdrantClusterSpec) {
 		*out = new(WriteCluster)
 		**out = **in
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(Autoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageAutoscaling != nil {
+		in, out := &in.StorageAutoscaling, &out.StorageAutoscaling
+		*out = new(StorageAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]OperationRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DecommissionNodeIndexes != nil {
+		in, out := &in.DecommissionNodeIndexes, &out.DecommissionNodeIndexes
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeOverrides != nil {
+		in, out := &in.NodeOverrides, &out.NodeOverrides
+		*out = make([]NodeOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QdrantClusterSpec.
@@ -1180,6 +1785,13 @@ func (in *QdrantClusterSpec) DeepCopy() *QdrantClusterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *QdrantClusterStatus) DeepCopyInto(out *QdrantClusterStatus) {
 	*out = *in
+	if in.PhaseHistory != nil {
+		in, out := &in.PhaseHistory, &out.PhaseHistory
+		*out = make([]PhaseTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.AvailableNodeIndexes != nil {
 		in, out := &in.AvailableNodeIndexes, &out.AvailableNodeIndexes
 		*out = make([]int, len(*in))
@@ -1195,6 +1807,11 @@ func (in *QdrantClusterStatus) DeepCopyInto(out *QdrantClusterStatus) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.LastAllocatedNodeIndex != nil {
+		in, out := &in.LastAllocatedNodeIndex, &out.LastAllocatedNodeIndex
+		*out = new(int)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -1220,10 +1837,51 @@ func (in *QdrantClusterStatus) DeepCopyInto(out *QdrantClusterStatus) {
 		*out = new(int)
 		**out = **in
 	}
-	if in.Selector != nil {
-		in, out := &in.Selector, &out.Selector
-		*out = new(string)
-		**out = **in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(string)
+		**out = **in
+	}
+	if in.Collections != nil {
+		in, out := &in.Collections, &out.Collections
+		*out = new(CollectionsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Update != nil {
+		in, out := &in.Update, &out.Update
+		*out = new(UpdateStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeferredOperations != nil {
+		in, out := &in.DeferredOperations, &out.DeferredOperations
+		*out = make([]DeferredOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]OperationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Decommissions != nil {
+		in, out := &in.Decommissions, &out.Decommissions
+		*out = make([]NodeDecommissionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePoolStatus, len(*in))
+		copy(*out, *in)
 	}
 }
 
@@ -1700,6 +2358,30 @@ func (in *QdrantSecurityContext) DeepCopy() *QdrantSecurityContext {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QdrantSnapshotInfo) DeepCopyInto(out *QdrantSnapshotInfo) {
+	*out = *in
+	if in.ShardId != nil {
+		in, out := &in.ShardId, &out.ShardId
+		*out = new(int)
+		**out = **in
+	}
+	if in.CreationTime != nil {
+		in, out := &in.CreationTime, &out.CreationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QdrantSnapshotInfo.
+func (in *QdrantSnapshotInfo) DeepCopy() *QdrantSnapshotInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(QdrantSnapshotInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReadCluster) DeepCopyInto(out *ReadCluster) {
 	*out = *in
@@ -1740,6 +2422,25 @@ func (in *RegionCapabilities) DeepCopy() *RegionCapabilities {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedSnapshot) DeepCopyInto(out *ResolvedSnapshot) {
+	*out = *in
+	if in.SnapshotTime != nil {
+		in, out := &in.SnapshotTime, &out.SnapshotTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedSnapshot.
+func (in *ResolvedSnapshot) DeepCopy() *ResolvedSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceRequests) DeepCopyInto(out *ResourceRequests) {
 	*out = *in
@@ -1771,9 +2472,59 @@ func (in *Resources) DeepCopy() *Resources {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreClusterTemplate) DeepCopyInto(out *RestoreClusterTemplate) {
+	*out = *in
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = new(int)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(Resources)
+		**out = **in
+	}
+	if in.StorageClassNames != nil {
+		in, out := &in.StorageClassNames, &out.StorageClassNames
+		*out = new(StorageClassNames)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(KubernetesService)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(Ingress)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreClusterTemplate.
+func (in *RestoreClusterTemplate) DeepCopy() *RestoreClusterTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreClusterTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RestoreDestination) DeepCopyInto(out *RestoreDestination) {
 	*out = *in
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(RestoreClusterTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreDestination.
@@ -1789,6 +2540,15 @@ func (in *RestoreDestination) DeepCopy() *RestoreDestination {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RestoreSource) DeepCopyInto(out *RestoreSource) {
 	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Before != nil {
+		in, out := &in.Before, &out.Before
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreSource.
@@ -1801,6 +2561,151 @@ func (in *RestoreSource) DeepCopy() *RestoreSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3CredentialsSecretRef) DeepCopyInto(out *S3CredentialsSecretRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3CredentialsSecretRef.
+func (in *S3CredentialsSecretRef) DeepCopy() *S3CredentialsSecretRef {
+	if in == nil {
+		return nil
+	}
+	out := new(S3CredentialsSecretRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3Destination) DeepCopyInto(out *S3Destination) {
+	*out = *in
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(S3CredentialsSecretRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Destination.
+func (in *S3Destination) DeepCopy() *S3Destination {
+	if in == nil {
+		return nil
+	}
+	out := new(S3Destination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShardReplicaStatus) DeepCopyInto(out *ShardReplicaStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShardReplicaStatus.
+func (in *ShardReplicaStatus) DeepCopy() *ShardReplicaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ShardReplicaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShardStatus) DeepCopyInto(out *ShardStatus) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]ShardReplicaStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShardStatus.
+func (in *ShardStatus) DeepCopy() *ShardStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ShardStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShardTransferStatus) DeepCopyInto(out *ShardTransferStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShardTransferStatus.
+func (in *ShardTransferStatus) DeepCopy() *ShardTransferStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ShardTransferStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotDestination) DeepCopyInto(out *SnapshotDestination) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3Destination)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotDestination.
+func (in *SnapshotDestination) DeepCopy() *SnapshotDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRetentionPolicy) DeepCopyInto(out *SnapshotRetentionPolicy) {
+	*out = *in
+	if in.KeepLast != nil {
+		in, out := &in.KeepLast, &out.KeepLast
+		*out = new(int)
+		**out = **in
+	}
+	if in.KeepDaily != nil {
+		in, out := &in.KeepDaily, &out.KeepDaily
+		*out = new(int)
+		**out = **in
+	}
+	if in.KeepWeekly != nil {
+		in, out := &in.KeepWeekly, &out.KeepWeekly
+		*out = new(int)
+		**out = **in
+	}
+	if in.KeepMonthly != nil {
+		in, out := &in.KeepMonthly, &out.KeepMonthly
+		*out = new(int)
+		**out = **in
+	}
+	if in.KeepYearly != nil {
+		in, out := &in.KeepYearly, &out.KeepYearly
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRetentionPolicy.
+func (in *SnapshotRetentionPolicy) DeepCopy() *SnapshotRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Storage) DeepCopyInto(out *Storage) {
 	*out = *in
@@ -1857,6 +2762,26 @@ func (in *Storage) DeepCopy() *Storage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageAutoscaling) DeepCopyInto(out *StorageAutoscaling) {
+	*out = *in
+	if in.UsageThreshold != nil {
+		in, out := &in.UsageThreshold, &out.UsageThreshold
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageAutoscaling.
+func (in *StorageAutoscaling) DeepCopy() *StorageAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageClass) DeepCopyInto(out *StorageClass) {
 	*out = *in
@@ -2008,6 +2933,81 @@ func (in *TraefikConfig) DeepCopy() *TraefikConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateStatus) DeepCopyInto(out *UpdateStatus) {
+	*out = *in
+	if in.CanaryNodeIndex != nil {
+		in, out := &in.CanaryNodeIndex, &out.CanaryNodeIndex
+		*out = new(int)
+		**out = **in
+	}
+	if in.CanaryHealthySince != nil {
+		in, out := &in.CanaryHealthySince, &out.CanaryHealthySince
+		*out = (*in).DeepCopy()
+	}
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateStatus.
+func (in *UpdateStatus) DeepCopy() *UpdateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateStrategy) DeepCopyInto(out *UpdateStrategy) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Partition != nil {
+		in, out := &in.Partition, &out.Partition
+		*out = new(int)
+		**out = **in
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryUpdate)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateStrategy.
+func (in *UpdateStrategy) DeepCopy() *UpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerticalAutoscaling) DeepCopyInto(out *VerticalAutoscaling) {
+	*out = *in
+	out.Min = in.Min
+	out.Max = in.Max
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerticalAutoscaling.
+func (in *VerticalAutoscaling) DeepCopy() *VerticalAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(VerticalAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeAttributesClass) DeepCopyInto(out *VolumeAttributesClass) {
 	*out = *in
@@ -2048,6 +3048,16 @@ func (in *VolumeSnapshotClass) DeepCopy() *VolumeSnapshotClass {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeSnapshotInfo) DeepCopyInto(out *VolumeSnapshotInfo) {
 	*out = *in
+	if in.NodeIndex != nil {
+		in, out := &in.NodeIndex, &out.NodeIndex
+		*out = new(int)
+		**out = **in
+	}
+	if in.RestoreSize != nil {
+		in, out := &in.RestoreSize, &out.RestoreSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 	if in.Error != nil {
 		in, out := &in.Error, &out.Error
 		*out = new(volumesnapshotv1.VolumeSnapshotError)
@@ -2086,3 +3096,28 @@ func (in *WriteCluster) DeepCopy() *WriteCluster {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WriteLockStatus) DeepCopyInto(out *WriteLockStatus) {
+	*out = *in
+	in.LockedAt.DeepCopyInto(&out.LockedAt)
+	if in.UnlockedAt != nil {
+		in, out := &in.UnlockedAt, &out.UnlockedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WriteLockStatus.
+func (in *WriteLockStatus) DeepCopy() *WriteLockStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WriteLockStatus)
+	in.DeepCopyInto(out)
+	return out
+}